@@ -5,6 +5,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ocmv1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
 )
 
@@ -59,18 +60,42 @@ type Verification struct {
 	Value string `json:"value,omitempty"`
 }
 
-// ResourceID defines the configuration of the repository.
+// ResourceID identifies a resource of a component version either by reference or by selector.
+// +kubebuilder:validation:XValidation:rule="(has(self.byReference) && has(self.byReference.resource) && size(self.byReference.resource) > 0) != has(self.bySelector)",message="exactly one of byReference or bySelector must be set"
 type ResourceID struct {
-	// +required
+	// ByReference identifies the resource by its identity, optionally in a referenced component version.
+	// +optional
 	ByReference ResourceReference `json:"byReference,omitempty"`
-	// TODO: Implement BySelector (see https://github.com/open-component-model/ocm-project/issues/296)
+	// BySelector selects the single resource of the component version that matches all criteria of the selector.
+	// +optional
+	BySelector *ResourceSelector `json:"bySelector,omitempty"`
+}
+
+// ResourceSelector selects a resource of a component version by its attributes. A resource is selected if it matches
+// all specified criteria.
+type ResourceSelector struct {
+	// Name is a regular expression the whole name of the resource must match.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Type of the resource, e.g. helmChart.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// ExtraIdentity contains attributes the extra identity of the resource must contain with the given values.
+	// +optional
+	ExtraIdentity map[string]string `json:"extraIdentity,omitempty"`
+	// Labels contains labels the resource must have with the given (string) values.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // ResourceReference defines a reference to a resource akin to the OCM Specification.
 // For more details see dedicated guide in the Specification:
 // https://github.com/open-component-model/ocm-spec/blob/main/doc/05-guidelines/03-references.md#references
 type ResourceReference struct {
-	Resource      ocmv1.Identity   `json:"resource"`
+	// Resource is the identity of the resource. It is optional in the schema, so that an unset ByReference of a
+	// ResourceID can be serialized. The ResourceID requires it unless the resource is selected by selector.
+	// +optional
+	Resource      ocmv1.Identity   `json:"resource,omitempty"`
 	ReferencePath []ocmv1.Identity `json:"referencePath,omitempty"`
 }
 
@@ -98,6 +123,30 @@ type ResourceInfo struct {
 	Digest string `json:"digest,omitempty"`
 }
 
+// Artifact is a blob stored by the controller and served by its artifact server. It is a gzip compressed tarball, so
+// that it can be fetched like the artifacts of Flux sources.
+type Artifact struct {
+	// URL is the HTTP address the artifact is served at.
+	// +required
+	URL string `json:"url"`
+
+	// Revision is a human-readable identifier of the content of the artifact, e.g. the version of a resource.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Digest is the digest of the artifact file in the format '<algorithm>:<checksum>'.
+	// +required
+	Digest string `json:"digest"`
+
+	// Size is the number of bytes of the artifact file.
+	// +optional
+	Size *int64 `json:"size,omitempty"`
+
+	// LastUpdateTime is the time the artifact was stored.
+	// +required
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
 type SourceReference struct {
 	// +required
 	Registry string `json:"registry"`
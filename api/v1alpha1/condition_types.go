@@ -28,6 +28,13 @@ const (
 	// CreateOrUpdateFailedReason is used when we fail to create or update a resource.
 	CreateOrUpdateFailedReason = "CreateOrUpdateFailed"
 
+	// ApplyConflictReason is used when applying an object conflicts with fields managed by another field manager.
+	ApplyConflictReason = "ApplyConflict"
+
+	// AccessDeniedReason is used when the Deployer is not permitted to deploy an object, e.g. because the impersonated
+	// service account lacks the required RBAC permissions.
+	AccessDeniedReason = "AccessDenied"
+
 	// GetReferenceFailedReason is used when we fail to get a reference.
 	GetReferenceFailedReason = "GetReferenceFailed"
 
@@ -37,6 +44,102 @@ const (
 	// StatusSetFailedReason is used when we fail to set the component status.
 	StatusSetFailedReason = "StatusSetFailed"
 
+	// StorageOperationFailedReason is used when we fail to store, push or delete an artifact.
+	StorageOperationFailedReason = "StorageOperationFailed"
+
 	// DeletionFailedReason is used when we fail to delete the resource.
 	DeletionFailedReason = "DeletionFailed"
+
+	// ObjectsNotReadyReason is used when the Deployer waits for deployed objects to become ready.
+	ObjectsNotReadyReason = "ObjectsNotReady"
+
+	// HealthCheckFailedReason is used when objects applied by the Deployer failed or did not become healthy in time.
+	HealthCheckFailedReason = "HealthCheckFailed"
+
+	// OwnershipConflictReason is used when objects of a Deployer are already controlled by another owner.
+	OwnershipConflictReason = "OwnershipConflict"
+
+	// DependencyNotReadyReason is used when Deployers the Deployer depends on are not ready yet.
+	DependencyNotReadyReason = "DependencyNotReady"
+
+	// DependencyCycleReason is used when the dependencies of a Deployer form a cycle.
+	DependencyCycleReason = "DependencyCycle"
+
+	// InstanceNotReadyReason is used when kro did not (yet) reconcile the instance created by a Deployer.
+	InstanceNotReadyReason = "InstanceNotReady"
+
+	// ValidationFailedReason is used when objects of a Deployer do not conform to the schema of the cluster.
+	ValidationFailedReason = "ValidationFailed"
+
+	// RolledBackReason is used when a failed revision was rolled back to the last healthy revision of the Deployer.
+	RolledBackReason = "RolledBack"
+
+	// RollbackFailedReason is used when we fail to re-apply the last healthy revision of the Deployer.
+	RollbackFailedReason = "RollbackFailed"
+
+	// InstancesExistReason is used when the deletion of a Deployer waits for the instances of a deployed
+	// ResourceGraphDefinition to be deleted.
+	InstancesExistReason = "InstancesExist"
+
+	// CleanupSkippedReason is used when the deployed objects of a deleted Deployer cannot be cleaned up, because the
+	// kubeconfig or the service account to access them does not exist anymore.
+	CleanupSkippedReason = "CleanupSkipped"
+
+	// PruneFailedReason is used when we fail to delete objects that are no longer part of the deployed manifest.
+	PruneFailedReason = "PruneFailed"
+
+	// HelmReleaseFailedReason is used when we fail to install or upgrade a Helm release.
+	HelmReleaseFailedReason = "HelmReleaseFailed"
+
+	// KustomizeBuildFailedReason is used when we fail to build a kustomization.
+	KustomizeBuildFailedReason = "KustomizeBuildFailed"
+
+	// DecryptionFailedReason is used when we fail to decrypt the encrypted objects of a deployer.
+	DecryptionFailedReason = "DecryptionFailed"
+
+	// PostRenderFailedReason is used when we fail to patch the objects of a deployer.
+	PostRenderFailedReason = "PostRenderFailed"
+
+	// GetValuesFailedReason is used when we fail to get the values referenced by a deployer.
+	GetValuesFailedReason = "GetValuesFailed"
+
+	// DryRunFailedReason is used when we fail to dry-run apply the objects of a deployer.
+	DryRunFailedReason = "DryRunFailed"
+
+	// DriftDetectionFailedReason is used when we fail to compare the applied objects with the desired state.
+	DriftDetectionFailedReason = "DriftDetectionFailed"
+
+	// DriftDetectedReason is used when applied objects were changed outside of the deployer.
+	DriftDetectedReason = "DriftDetected"
+
+	// DriftRevertedReason is used when changes to applied objects made outside of the deployer were reverted.
+	DriftRevertedReason = "DriftReverted"
+
+	// ResourceGraphDefinitionNotReadyReason is used when kro did not (yet) accept the applied resource graph definition.
+	ResourceGraphDefinitionNotReadyReason = "ResourceGraphDefinitionNotReady"
+)
+
+// Conditions mirrored from the ResourceGraphDefinition applied by a Deployer.
+const (
+	// GraphVerifiedCondition indicates whether kro accepted the graph of the applied resource graph definition.
+	GraphVerifiedCondition = "GraphVerified"
+
+	// CustomResourceDefinitionSyncedCondition indicates whether kro registered the custom resource definition
+	// generated from the applied resource graph definition.
+	CustomResourceDefinitionSyncedCondition = "CustomResourceDefinitionSynced"
+
+	// ReconcilerReadyCondition indicates whether kro started the controller for the instances of the applied
+	// resource graph definition.
+	ReconcilerReadyCondition = "ReconcilerReady"
+)
+
+const (
+	// DriftedCondition indicates whether objects applied by a Deployer were changed outside of the Deployer.
+	DriftedCondition = "Drifted"
+
+	// InstanceReadyCondition indicates whether kro reconciled the instance created by a Deployer.
+	InstanceReadyCondition = "InstanceReady"
+
+	// HealthyCondition indicates whether the objects applied by a Deployer are healthy.
+	HealthyCondition = "Healthy"
 )
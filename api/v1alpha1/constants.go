@@ -27,4 +27,7 @@ const (
 	// OCMRepositoryFinalizer makes sure that the OCM repository is only deleted when it is no longer referenced by any
 	// other component.
 	OCMRepositoryFinalizer = "finalizers.ocm.software/ocmrepository"
+	// DeployerFinalizer makes sure that the objects deployed by a deployer are removed before the deployer is
+	// deleted.
+	DeployerFinalizer = "finalizers.ocm.software/deployer"
 )
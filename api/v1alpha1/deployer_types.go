@@ -2,29 +2,563 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/fluxcd/pkg/apis/meta"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const KindDeployer = "Deployer"
 
-// DeployerSpec defines the desired state of Deployer.
-type DeployerSpec struct {
-	// ResourceRef is the k8s resource name of an OCM resource containing the ResourceGroupDefinition.
+// DeployerType defines how the content of the referenced resource is deployed.
+type DeployerType string
+
+const (
+	// DeployerTypeResourceGraphDefinition deploys the content of the resource as kro ResourceGraphDefinition.
+	DeployerTypeResourceGraphDefinition DeployerType = "ResourceGraphDefinition"
+	// DeployerTypeManifest deploys every object of a (multi-document) YAML or JSON manifest. Namespaced objects without
+	// namespace are deployed into the namespace of the (first) referenced Resource. The controller must be permitted to
+	// manage all kinds of objects contained in the manifest.
+	DeployerTypeManifest DeployerType = "Manifest"
+	// DeployerTypeHelm installs the content of the resource as Helm chart. The resource is either a chart archive or
+	// an OCI artifact containing the chart.
+	DeployerTypeHelm DeployerType = "Helm"
+	// DeployerTypeKustomize builds the kustomization contained in the resource and deploys the resulting objects. The
+	// resource is a (compressed) tar archive of a directory, and the controller must be permitted to manage all kinds
+	// of objects contained in the kustomization.
+	DeployerTypeKustomize DeployerType = "Kustomize"
+)
+
+// DeployerMode defines whether the Deployer applies the objects or only previews the changes.
+type DeployerMode string
+
+const (
+	// DeployerModeApply applies the objects to the cluster.
+	DeployerModeApply DeployerMode = "Apply"
+	// DeployerModeDryRun applies the objects with a server-side dry-run and reports the changes in the status
+	// without persisting them.
+	DeployerModeDryRun DeployerMode = "DryRun"
+)
+
+// DeployerResource references an OCM resource deployed by a Deployer.
+type DeployerResource struct {
+	// ResourceRef is the k8s resource name of an OCM resource containing the objects to deploy.
 	// +required
 	ResourceRef ObjectKey `json:"resourceRef"`
 
+	// WaitForReady blocks the deployment of the following resources until all objects of this resource are ready.
+	// +optional
+	WaitForReady bool `json:"waitForReady,omitempty"`
+}
+
+// HealthCheckSpec configures the health assessment of the objects deployed by a Deployer.
+type HealthCheckSpec struct {
+	// Timeout is the duration after which objects that did not become healthy since they were applied are reported
+	// unhealthy.
+	// +kubebuilder:default:="5m"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// KubeConfigReference references the kubeconfig of a remote cluster.
+type KubeConfigReference struct {
+	// SecretRef references the Secret containing the kubeconfig in the namespace of the (first) referenced Resource.
+	// If no key is specified, the keys "value" and "value.yaml" are tried in this order. The kubeconfig must embed its
+	// credentials and certificates, references to files, exec and auth provider plugins are rejected.
+	// +required
+	SecretRef meta.SecretKeyReference `json:"secretRef"`
+}
+
+// DecryptionProvider defines how the encrypted objects of a Deployer are decrypted.
+type DecryptionProvider string
+
+const (
+	// DecryptionProviderSOPS decrypts YAML documents encrypted with SOPS.
+	DecryptionProviderSOPS DecryptionProvider = "sops"
+)
+
+// DecryptionSpec configures the decryption of the encrypted objects of a Deployer.
+type DecryptionSpec struct {
+	// Provider of the encryption.
+	// +kubebuilder:validation:Enum:="sops"
+	// +required
+	Provider DecryptionProvider `json:"provider"`
+
+	// SecretRef references the Secret containing the private keys in the namespace of the (first) referenced Resource.
+	// Keys ending with .agekey contain age identities, keys ending with .asc contain armored PGP private keys. PGP keys
+	// are imported with gpg, which must be available in the image of the controller.
+	// +optional
+	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// DeletionPolicy defines what happens to the deployed objects when the Deployer is deleted.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the deployed objects together with the Deployer.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the deployed objects and removes the Deployer as their owner.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyWaitForInstances deletes the deployed objects like DeletionPolicyDelete, but blocks the deletion
+	// as long as instances of the custom resource generated by a deployed ResourceGraphDefinition exist. The controller
+	// is only permitted to list instances in the kro.run group, ResourceGraphDefinitions with a custom schema group
+	// require an additional role that permits listing their instances.
+	DeletionPolicyWaitForInstances DeletionPolicy = "WaitForInstances"
+)
+
+// DeployerSpec defines the desired state of Deployer.
+// +kubebuilder:validation:XValidation:rule="(has(self.resourceRef) && has(self.resourceRef.name)) != (has(self.resources) && size(self.resources) > 0)",message="exactly one of resourceRef or resources must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.instance) || !has(self.type) || self.type == 'ResourceGraphDefinition'",message="instance is only supported for deployers of type ResourceGraphDefinition"
+// +kubebuilder:validation:XValidation:rule="!has(self.postRender) || !has(self.type) || self.type != 'Helm'",message="postRender is not supported for deployers of type Helm"
+// +kubebuilder:validation:XValidation:rule="!has(self.decryption) || !has(self.type) || self.type != 'Helm'",message="decryption is not supported for deployers of type Helm"
+// +kubebuilder:validation:XValidation:rule="!(has(self.rollbackOnFailure) && self.rollbackOnFailure && has(self.type) && self.type == 'Helm')",message="rollbackOnFailure is not supported for deployers of type Helm"
+// +kubebuilder:validation:XValidation:rule="!has(self.serviceAccountName) || !has(self.kubeConfig)",message="serviceAccountName is not supported for deployers with a kubeConfig, the identity in the remote cluster is defined by the kubeconfig"
+type DeployerSpec struct {
+	// ResourceRef is the k8s resource name of an OCM resource containing the ResourceGroupDefinition or manifest.
+	// Either ResourceRef or Resources must be set.
+	// +optional
+	ResourceRef ObjectKey `json:"resourceRef,omitempty"`
+
+	// Resources is an ordered list of OCM resources to deploy. The resources are deployed one after another, so that
+	// e.g. custom resource definitions can be deployed before the objects using them. All resources are deployed with
+	// the same type. Either ResourceRef or Resources must be set.
+	// +optional
+	Resources []DeployerResource `json:"resources,omitempty"`
+
+	// Type defines how the content of the referenced resource is deployed. ResourceGraphDefinition expects a single
+	// kro ResourceGraphDefinition, Manifest accepts any (multi-document) YAML or JSON manifest of Kubernetes objects,
+	// Helm installs a Helm chart as release, and Kustomize builds a kustomization directory.
+	// +kubebuilder:validation:Enum:="ResourceGraphDefinition";"Manifest";"Helm";"Kustomize"
+	// +kubebuilder:default:="ResourceGraphDefinition"
+	// +optional
+	Type DeployerType `json:"type,omitempty"`
+
+	// Instance configures an instance of the custom resource generated by the ResourceGraphDefinition of a Deployer
+	// of type ResourceGraphDefinition. If set, the Deployer creates the instance once kro accepted the
+	// ResourceGraphDefinition. The controller is only permitted to manage instances in the kro.run group,
+	// ResourceGraphDefinitions with a custom schema group require an additional role that permits to get, create,
+	// patch, and delete their instances (or the service account the Deployer impersonates must be permitted to).
+	// +optional
+	Instance *InstanceSpec `json:"instance,omitempty"`
+
+	// Helm configures the release of a Deployer of type Helm.
+	// +optional
+	Helm *HelmSpec `json:"helm,omitempty"`
+
+	// Kustomize configures the build of a Deployer of type Kustomize.
+	// +optional
+	Kustomize *KustomizeSpec `json:"kustomize,omitempty"`
+
+	// Decryption configures the decryption of YAML documents of the resources that are encrypted, e.g. Secrets that are
+	// shipped encrypted in a component. The documents are decrypted after the resources were verified, so that the
+	// plaintext only exists in the cluster. The YAML files of a kustomization are decrypted before it is built. Not
+	// supported for Deployers of type Helm.
+	// +optional
+	Decryption *DecryptionSpec `json:"decryption,omitempty"`
+
+	// PostRender patches the objects of the resources after they were verified and decoded and before they are
+	// applied, e.g. to make environment specific changes to the objects of a signed component. Not supported for
+	// Deployers of type Helm.
+	// +optional
+	PostRender *PostRenderSpec `json:"postRender,omitempty"`
+
+	// Mode defines whether the objects are applied or only dry-run applied. In DryRun mode, the changes that would be
+	// made to the cluster are reported in the status of the Deployer.
+	// +kubebuilder:validation:Enum:="Apply";"DryRun"
+	// +kubebuilder:default:="Apply"
+	// +optional
+	Mode DeployerMode `json:"mode,omitempty"`
+
 	// OCMConfig defines references to secrets, config maps or ocm api
 	// objects providing configuration data including credentials.
 	// +optional
 	OCMConfig []OCMConfiguration `json:"ocmConfig,omitempty"`
 
+	// Prune enables the garbage collection of objects that were applied by a previous revision of the deployer but are
+	// no longer part of the current one.
+	// +kubebuilder:default:=true
+	// +optional
+	Prune *bool `json:"prune,omitempty"`
+
+	// Force enables taking over the ownership of fields that are managed by another field manager when applying
+	// objects. Otherwise, such conflicts fail the reconciliation.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// Interval at which the applied objects are checked for drift. If not set, the objects are only checked when the
+	// Deployer or the referenced Resource changes.
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// RevertDrift enables reverting changes that were made to the applied objects outside of the Deployer. Otherwise,
+	// such changes are only reported in the Drifted condition.
+	// +optional
+	RevertDrift bool `json:"revertDrift,omitempty"`
+
+	// AdoptObjects enables taking over objects that already exist and are controlled by another owner, e.g. another
+	// Deployer. Otherwise, such objects are not applied and the conflict is reported with the OwnershipConflict reason.
+	// +optional
+	AdoptObjects bool `json:"adoptObjects,omitempty"`
+
+	// ServiceAccountName is the name of a service account in the namespace of the (first) referenced Resource. The
+	// controller impersonates the service account to deploy the objects, so that only the permissions granted to the
+	// service account apply. If not set, the default service account configured for the controller is impersonated.
+	// The controller is only permitted to impersonate the service accounts of namespaces that bind the
+	// deployer-impersonator ClusterRole to it. The field is not supported together with KubeConfig, as the identity in
+	// the remote cluster is defined by the kubeconfig.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// KubeConfig references the kubeconfig of a remote cluster to deploy the objects to. The resources are still
+	// fetched and verified in the cluster of the controller. If not set, the objects are deployed to the cluster of
+	// the controller.
+	// +optional
+	KubeConfig *KubeConfigReference `json:"kubeConfig,omitempty"`
+
+	// DeletionPolicy defines what happens to the deployed objects when the Deployer is deleted. If the kubeconfig
+	// secret or the service account of the Deployer does not exist anymore, the objects cannot be accessed and are
+	// left behind regardless of the policy.
+	// +kubebuilder:validation:Enum:="Delete";"Orphan";"WaitForInstances"
+	// +kubebuilder:default:="Delete"
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// HealthCheck enables the health assessment of the applied objects and of the instance created by the Deployer
+	// (see Instance). Instances created by others are not assessed. The result is reported in the Healthy condition.
+	// +optional
+	HealthCheck *HealthCheckSpec `json:"healthCheck,omitempty"`
+
+	// RollbackOnFailure enables re-applying the last healthy revision if a new revision fails to be built or applied,
+	// or if its objects do not become healthy within the timeout of the health check. The failed revision is not
+	// retried until the resources or the spec of the Deployer change. Not supported for Deployers of type Helm.
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+
+	// DependsOn references other Deployers that must be ready at their current generation before the objects of this
+	// Deployer are deployed.
+	// +optional
+	DependsOn []corev1.LocalObjectReference `json:"dependsOn,omitempty"`
+
 	// Suspend tells the controller to suspend the reconciliation of this
 	// Resource.
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 }
 
+// HelmSpec configures the Helm release installed by a Deployer.
+type HelmSpec struct {
+	// ReleaseName is the name of the Helm release. Defaults to the name of the Deployer. Changing the name or the
+	// namespace of the release uninstalls the installed release before the release is installed again.
+	// +kubebuilder:validation:MaxLength:=53
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// Namespace the Helm release is installed into. Defaults to the namespace of the (first) referenced Resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// CreateNamespace creates the namespace of the release if it does not exist.
+	// +optional
+	CreateNamespace bool `json:"createNamespace,omitempty"`
+
+	// ValuesFrom references config maps or secrets containing the values of the release. Later values are merged on
+	// top of earlier ones.
+	// +optional
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+}
+
+// InstanceSpec configures the instance of the custom resource generated by a ResourceGraphDefinition.
+type InstanceSpec struct {
+	// Name of the instance. Defaults to the name of the Deployer.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the instance. Defaults to the namespace of the (first) referenced Resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Values are the inline values of the spec of the instance.
+	// +optional
+	Values *apiextensionsv1.JSON `json:"values,omitempty"`
+
+	// ValuesFrom references config maps or secrets containing values of the spec of the instance. They are merged
+	// on top of the inline values, later values on top of earlier ones.
+	// +optional
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+}
+
+// ValuesReference references a config map or secret containing YAML values.
+type ValuesReference struct {
+	// Kind of the referenced object.
+	// +kubebuilder:validation:Enum:="ConfigMap";"Secret"
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the referenced object. Defaults to the namespace of the (first) referenced Resource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// ValuesKey is the key of the values in the referenced object.
+	// +kubebuilder:default:="values.yaml"
+	// +optional
+	ValuesKey string `json:"valuesKey,omitempty"`
+
+	// Optional marks the reference as optional. A missing object or key is ignored instead of failing the
+	// reconciliation.
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
+// KustomizeSpec configures the build of the kustomization deployed by a Deployer.
+type KustomizeSpec struct {
+	// Path of the directory containing the kustomization file, relative to the root of the resource. Defaults to the
+	// root of the resource.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Patches are applied to the objects of the kustomization. Each patch is either a strategic merge patch or a JSON
+	// 6902 patch.
+	// +optional
+	Patches []KustomizePatch `json:"patches,omitempty"`
+
+	// Images override the names, tags, or digests of the container images used by the objects of the kustomization.
+	// +optional
+	Images []KustomizeImage `json:"images,omitempty"`
+}
+
+// PostRenderSpec configures the patches applied to the objects of a Deployer before they are applied.
+type PostRenderSpec struct {
+	// Patches are applied to the objects of the Deployer. Each patch is either a strategic merge patch or a JSON 6902
+	// patch.
+	// +optional
+	Patches []KustomizePatch `json:"patches,omitempty"`
+
+	// CommonLabels are added to the metadata of all objects of the Deployer. Selectors and templates are not changed.
+	// +optional
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations are added to the metadata of all objects of the Deployer.
+	// +optional
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+}
+
+// KustomizePatch is a patch applied to the objects selected by the target.
+type KustomizePatch struct {
+	// Patch contains a strategic merge patch or a JSON 6902 patch in YAML or JSON.
+	// +required
+	Patch string `json:"patch"`
+
+	// Target selects the objects the patch is applied to. A strategic merge patch without target is applied to the
+	// object it identifies.
+	// +optional
+	Target *KustomizeSelector `json:"target,omitempty"`
+}
+
+// KustomizeSelector selects objects of a kustomization. Group, version, kind, name, and namespace are matched as
+// regular expressions.
+type KustomizeSelector struct {
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// LabelSelector is a label selector expression the labels of the objects must match.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// AnnotationSelector is a label selector expression the annotations of the objects must match.
+	// +optional
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
+}
+
+// KustomizeImage overrides a container image of the objects of a kustomization.
+type KustomizeImage struct {
+	// Name of the image to override, without tag or digest.
+	// +required
+	Name string `json:"name"`
+	// NewName replaces the name of the image.
+	// +optional
+	NewName string `json:"newName,omitempty"`
+	// NewTag replaces the tag of the image.
+	// +optional
+	NewTag string `json:"newTag,omitempty"`
+	// Digest replaces the tag of the image with a digest. It takes precedence over NewTag.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// DeployedResourceStatus reports the deployment state of a resource of a Deployer.
+type DeployedResourceStatus struct {
+	// ResourceRef references the Resource that is deployed.
+	ResourceRef ObjectKey `json:"resourceRef"`
+
+	// Version is the version of the OCM resource.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Digest is the digest of the resource data.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Ready is true if the objects of the resource were applied and, if the Deployer waits for them, are ready.
+	Ready bool `json:"ready"`
+
+	// Message describes the deployment state of the resource.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DeployedRevision is a revision of the resources of a Deployer that was applied and found healthy.
+type DeployedRevision struct {
+	// Digest identifies the revision like LastAppliedDigest.
+	Digest string `json:"digest"`
+
+	// PostRenderDigest identifies the patched objects of the revision like LastAppliedPostRenderDigest.
+	// +optional
+	PostRenderDigest string `json:"postRenderDigest,omitempty"`
+
+	// Resources contains the applied manifest of every resource of the revision.
+	// +optional
+	Resources []DeployedResourceRevision `json:"resources,omitempty"`
+}
+
+// DeployedResourceRevision is the revision of a resource of a Deployer that was applied and found healthy.
+type DeployedResourceRevision struct {
+	// ResourceRef references the Resource that was deployed.
+	ResourceRef ObjectKey `json:"resourceRef"`
+
+	// ComponentVersion is the version of the component the resource belongs to.
+	// +optional
+	ComponentVersion string `json:"componentVersion,omitempty"`
+
+	// Version is the version of the OCM resource.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Digest is the digest of the resource data.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Manifest is the gzip compressed manifest of the objects that were applied for the resource. It is stored before
+	// the manifest is decrypted and post-rendered. For Deployers of type Kustomize, it is the kustomization of the
+	// resource, which is built again with the current Kustomize spec on rollback.
+	// +optional
+	Manifest []byte `json:"manifest,omitempty"`
+}
+
+// FailedRevision identifies a revision of the resources of a Deployer that was rolled back.
+type FailedRevision struct {
+	// Version is the version of the resources of the failed revision.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Digest identifies the failed revision like LastAppliedDigest.
+	Digest string `json:"digest"`
+
+	// ObservedGeneration is the generation of the Deployer with which the revision failed.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// InventoryEntry identifies an object that was applied by a Deployer.
+type InventoryEntry struct {
+	// APIVersion of the applied object.
+	// +required
+	APIVersion string `json:"apiVersion"`
+	// Kind of the applied object.
+	// +required
+	Kind string `json:"kind"`
+	// Namespace of the applied object. Empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the applied object.
+	// +required
+	Name string `json:"name"`
+}
+
+// DryRunSummary describes the changes a Deployer in DryRun mode would make to the cluster.
+type DryRunSummary struct {
+	// Digest of the resource that was dry-run applied.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+	// Created contains the objects that would be created.
+	// +optional
+	Created []InventoryEntry `json:"created,omitempty"`
+	// Updated contains the objects that would be updated.
+	// +optional
+	Updated []InventoryEntry `json:"updated,omitempty"`
+	// Unchanged contains the objects that would not change.
+	// +optional
+	Unchanged []InventoryEntry `json:"unchanged,omitempty"`
+	// Pruned contains the objects that would be deleted as they are no longer part of the manifest.
+	// +optional
+	Pruned []InventoryEntry `json:"pruned,omitempty"`
+	// Patch contains the JSON merge patches of the updated objects. The patches of Secrets and of objects of encrypted
+	// documents are redacted. It is truncated if it exceeds the maximum size.
+	// +optional
+	Patch string `json:"patch,omitempty"`
+}
+
+// HelmReleaseStatus describes the Helm release installed by a Deployer.
+type HelmReleaseStatus struct {
+	// Name of the release.
+	// +required
+	Name string `json:"name"`
+	// Namespace of the release.
+	// +required
+	Namespace string `json:"namespace"`
+	// Revision of the release.
+	// +optional
+	Revision int `json:"revision,omitempty"`
+	// Status of the release as reported by Helm.
+	// +optional
+	Status string `json:"status,omitempty"`
+	// ChartName is the name of the installed chart.
+	// +optional
+	ChartName string `json:"chartName,omitempty"`
+	// ChartVersion is the version of the installed chart.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// ValuesDigest is the digest of the values the release was installed with.
+	// +optional
+	ValuesDigest string `json:"valuesDigest,omitempty"`
+}
+
+// InstanceStatus reports the state of the instance created by a Deployer.
+type InstanceStatus struct {
+	// APIVersion of the instance.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the instance.
+	Kind string `json:"kind"`
+
+	// Namespace of the instance.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the instance.
+	Name string `json:"name"`
+
+	// State is the state of the instance reported by kro, e.g. ACTIVE or IN_PROGRESS.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Status contains the fields of the status of the instance without its state and conditions.
+	// +optional
+	Status *apiextensionsv1.JSON `json:"status,omitempty"`
+}
+
 // DeployerStatus defines the observed state of Deployer.
 type DeployerStatus struct {
 	// ObservedGeneration is the last observed generation of the Deployer
@@ -41,6 +575,50 @@ type DeployerStatus struct {
 	// in the order the configuration data was applied.
 	// +optional
 	EffectiveOCMConfig []OCMConfiguration `json:"effectiveOCMConfig,omitempty"`
+
+	// Inventory contains the objects applied by the last reconciliation of the Deployer.
+	// +optional
+	Inventory []InventoryEntry `json:"inventory,omitempty"`
+
+	// LastAppliedDigest is the digest of the resource that was applied by the last reconciliation of the Deployer.
+	// If the Deployer deploys multiple resources, it is the digest of the digests of all resources.
+	// +optional
+	LastAppliedDigest string `json:"lastAppliedDigest,omitempty"`
+
+	// LastAppliedPostRenderDigest identifies the objects that were applied by the last reconciliation of the Deployer
+	// after they were patched by the post render patches. It is the digest of the manifests before they were decrypted
+	// and of the post render spec. It is empty if the Deployer does not patch its objects.
+	// +optional
+	LastAppliedPostRenderDigest string `json:"lastAppliedPostRenderDigest,omitempty"`
+
+	// LastAppliedTime is the time at which the Deployer applied the current revision of its resources or spec.
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// Resources reports the deployment state of every resource of the Deployer in the order they are deployed.
+	// +optional
+	Resources []DeployedResourceStatus `json:"resources,omitempty"`
+
+	// LastHealthyRevision is the last revision of the resources that was applied and found healthy. It is re-applied
+	// if a later revision fails and RollbackOnFailure is enabled.
+	// +optional
+	LastHealthyRevision *DeployedRevision `json:"lastHealthyRevision,omitempty"`
+
+	// FailedRevision is the revision that was rolled back to the LastHealthyRevision.
+	// +optional
+	FailedRevision *FailedRevision `json:"failedRevision,omitempty"`
+
+	// DryRun contains the changes the Deployer would make to the cluster if it is in DryRun mode.
+	// +optional
+	DryRun *DryRunSummary `json:"dryRun,omitempty"`
+
+	// Instance contains the state of the instance created by the Deployer.
+	// +optional
+	Instance *InstanceStatus `json:"instance,omitempty"`
+
+	// Helm contains the state of the Helm release of a Deployer of type Helm.
+	// +optional
+	Helm *HelmReleaseStatus `json:"helm,omitempty"`
 }
 
 func (in *Deployer) GetConditions() []metav1.Condition {
@@ -59,6 +637,21 @@ func (in *Deployer) GetVID() map[string]string {
 	return metadata
 }
 
+// GetResources returns the resources to deploy in the order they are deployed.
+func (in Deployer) GetResources() []DeployerResource {
+	if len(in.Spec.Resources) > 0 {
+		return in.Spec.Resources
+	}
+
+	return []DeployerResource{{ResourceRef: in.Spec.ResourceRef}}
+}
+
+// GetRequeueAfter returns the duration after which the Deployer must be
+// reconciled again.
+func (in Deployer) GetRequeueAfter() time.Duration {
+	return in.Spec.Interval.Duration
+}
+
 func (in *Deployer) SetObservedGeneration(v int64) {
 	in.Status.ObservedGeneration = v
 }
@@ -83,7 +676,8 @@ func (in *Deployer) GetEffectiveOCMConfig() []OCMConfiguration {
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
 
-// Deployer is the Schema for the deployers API.
+// Deployer is the Schema for the deployers API. Deployers are cluster-scoped and choose the namespace of the service
+// account they impersonate, so creating them is reserved for cluster administrators.
 type Deployer struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
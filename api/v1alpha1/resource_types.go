@@ -30,6 +30,13 @@ type ResourceSpec struct {
 	// expensive for large resources.
 	SkipVerify bool `json:"skipVerify,omitempty"`
 
+	// PushArtifact pushes the verified blob of the resource as Flux OCI artifact to the OCI registry configured for the
+	// controller. The source reference of the resource then points to the pushed artifact instead of the source of the
+	// blob. The pushed artifact is deleted when the blob changes or the Resource is deleted. Resources that are OCI
+	// images or Helm charts must not be pushed, as they are consumed from their source.
+	// +optional
+	PushArtifact bool `json:"pushArtifact,omitempty"`
+
 	// Interval at which the resource is checked for updates.
 	// +required
 	Interval metav1.Duration `json:"interval"`
@@ -61,6 +68,11 @@ type ResourceStatus struct {
 	// +optional
 	Component *ComponentInfo `json:"component,omitempty"`
 
+	// Artifact is the verified blob of the resource served by the artifact
+	// server of the controller.
+	// +optional
+	Artifact *Artifact `json:"artifact,omitempty"`
+
 	// EffectiveOCMConfig specifies the entirety of config maps and secrets
 	// whose configuration data was applied to the Resource reconciliation,
 	// in the order the configuration data was applied.
@@ -0,0 +1,154 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const KindResourceSet = "ResourceSet"
+
+// ResourceSetSpec defines the desired state of ResourceSet.
+type ResourceSetSpec struct {
+	// ComponentRef is a reference to a Component.
+	// +required
+	ComponentRef corev1.LocalObjectReference `json:"componentRef"`
+
+	// Selector selects the ocm resources a Resource is created for. An
+	// empty selector selects all resources.
+	// +optional
+	Selector ResourceSelector `json:"selector,omitempty"`
+
+	// Recursive selects the resources of the referenced component versions
+	// as well.
+	// +optional
+	Recursive bool `json:"recursive,omitempty"`
+
+	// OCMConfig defines references to secrets, config maps or ocm api
+	// objects providing configuration data including credentials. It is
+	// passed on to the created Resources.
+	// +optional
+	OCMConfig []OCMConfiguration `json:"ocmConfig,omitempty"`
+
+	// SkipVerify indicates whether the created Resources should be verified
+	// or not.
+	// +optional
+	SkipVerify bool `json:"skipVerify,omitempty"`
+
+	// Interval at which the component version is checked for updates. It is
+	// passed on to the created Resources.
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// Suspend tells the controller to suspend the reconciliation of this
+	// ResourceSet.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// ResourceSetEntry is a Resource created for an ocm resource selected by a
+// ResourceSet.
+type ResourceSetEntry struct {
+	// Name is the name of the Resource.
+	// +required
+	Name string `json:"name"`
+
+	// ResourceReference identifies the selected ocm resource.
+	ResourceReference `json:",inline"`
+}
+
+// ResourceSetStatus defines the observed state of ResourceSet.
+type ResourceSetStatus struct {
+	// ObservedGeneration is the last observed generation of the ResourceSet
+	// object.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the conditions for the ResourceSet.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Component is the component version the resources were selected from.
+	// +optional
+	Component *ComponentInfo `json:"component,omitempty"`
+
+	// Resources are the Resources created for the selected ocm resources.
+	// +optional
+	Resources []ResourceSetEntry `json:"resources,omitempty"`
+
+	// EffectiveOCMConfig specifies the entirety of config maps and secrets
+	// whose configuration data was applied to the ResourceSet reconciliation,
+	// in the order the configuration data was applied.
+	// +optional
+	EffectiveOCMConfig []OCMConfiguration `json:"effectiveOCMConfig,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// ResourceSet is the Schema for the resourcesets API. It creates a Resource
+// for each ocm resource of a component version matching its selector.
+type ResourceSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourceSetSpec   `json:"spec"`
+	Status ResourceSetStatus `json:"status,omitempty"`
+}
+
+func (in *ResourceSet) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+func (in *ResourceSet) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+func (in *ResourceSet) GetVID() map[string]string {
+	vid := fmt.Sprintf("%s:%s", in.GetNamespace(), in.GetName())
+	metadata := make(map[string]string)
+	metadata[GroupVersion.Group+"/resource_set_version"] = vid
+
+	return metadata
+}
+
+func (in *ResourceSet) SetObservedGeneration(v int64) {
+	in.Status.ObservedGeneration = v
+}
+
+func (in *ResourceSet) GetObjectMeta() *metav1.ObjectMeta {
+	return &in.ObjectMeta
+}
+
+func (in *ResourceSet) GetKind() string {
+	return KindResourceSet
+}
+
+// GetRequeueAfter returns the duration after which the ResourceSet must be
+// reconciled again.
+func (in ResourceSet) GetRequeueAfter() time.Duration {
+	return in.Spec.Interval.Duration
+}
+
+func (in *ResourceSet) GetSpecifiedOCMConfig() []OCMConfiguration {
+	return in.Spec.OCMConfig
+}
+
+func (in *ResourceSet) GetEffectiveOCMConfig() []OCMConfiguration {
+	return in.Status.EffectiveOCMConfig
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceSetList contains a list of ResourceSet.
+type ResourceSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ResourceSet{}, &ResourceSetList{})
+}
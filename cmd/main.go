@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -12,6 +13,7 @@ import (
 
 	"github.com/fluxcd/pkg/runtime/events"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -23,11 +25,13 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/component"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/deployer"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/ocmrepository"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/replication"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/resource"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/controller/resourceset"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
 )
 
@@ -52,6 +56,12 @@ func main() {
 		secureMetrics        bool
 		enableHTTP2          bool
 		eventsAddr           string
+		artifactPath         string
+		artifactAddr         string
+		artifactAdvAddr      string
+		registryAddr         string
+		registryInsecure     bool
+		defaultSA            string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metric endpoint binds to. "+
@@ -65,6 +75,21 @@ func main() {
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 	flag.StringVar(&eventsAddr, "events-addr", "", "The address of the events receiver.")
+	flag.StringVar(&artifactPath, "artifact-storage-path", "",
+		"The directory the artifacts of the resources are stored in. If empty, no artifacts are stored. The "+
+			"artifact server has no authentication and serves the artifacts to every client that reaches it.")
+	flag.StringVar(&artifactAddr, "artifact-bind-address", ":9090", "The address the artifact server binds to.")
+	flag.StringVar(&artifactAdvAddr, "artifact-adv-address", artifactAdvertisedAddress(),
+		"The address the artifact server is reachable at by consumers of the artifacts.")
+	flag.StringVar(&registryAddr, "oci-registry-address", "",
+		"The address of the OCI registry the resources that set spec.pushArtifact are pushed to. If empty, no "+
+			"resources are pushed. The registry must accept anonymous pushes and deletions of manifests, e.g. a "+
+			"registry in the cluster, as the artifacts of previous versions of a resource are deleted.")
+	flag.BoolVar(&registryInsecure, "oci-registry-insecure", false,
+		"If set, the resources are pushed to the OCI registry via plain HTTP.")
+	flag.StringVar(&defaultSA, "default-service-account", "default",
+		"The service account that is impersonated by Deployers that do not specify a service account. If empty, "+
+			"such Deployers deploy with the permissions of the controller, which is only safe in single-tenant clusters.")
 
 	opts := zap.Options{
 		Development: true,
@@ -152,12 +177,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	var storage *artifact.Storage
+	if artifactPath != "" {
+		if storage, err = artifact.NewStorage(artifactPath, artifactAdvAddr); err != nil {
+			setupLog.Error(err, "unable to create artifact storage")
+			os.Exit(1)
+		}
+
+		if err := mgr.Add(&artifact.Server{
+			Storage: storage,
+			Address: artifactAddr,
+			Elected: mgr.Elected(),
+			Client:  mgr.GetClient(),
+			Pod: types.NamespacedName{
+				Namespace: os.Getenv("RUNTIME_NAMESPACE"),
+				Name:      os.Getenv("POD_NAME"),
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to add artifact server")
+			os.Exit(1)
+		}
+	}
+
+	var registry *artifact.Registry
+	if registryAddr != "" {
+		registry = &artifact.Registry{Address: registryAddr, Insecure: registryInsecure}
+	}
+
 	if err = (&resource.Reconciler{
 		BaseReconciler: &ocm.BaseReconciler{
 			Client:        mgr.GetClient(),
 			Scheme:        mgr.GetScheme(),
 			EventRecorder: eventsRecorder,
 		},
+		Storage:  storage,
+		Registry: registry,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Resource")
 		os.Exit(1)
@@ -180,10 +234,23 @@ func main() {
 			Scheme:        mgr.GetScheme(),
 			EventRecorder: eventsRecorder,
 		},
+		RESTConfig:            mgr.GetConfig(),
+		DefaultServiceAccount: defaultSA,
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Deployer")
 		os.Exit(1)
 	}
+
+	if err = (&resourceset.Reconciler{
+		BaseReconciler: &ocm.BaseReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			EventRecorder: eventsRecorder,
+		},
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ResourceSet")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -208,3 +275,13 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// artifactAdvertisedAddress returns the address of the artifact service in the namespace the manager runs in.
+func artifactAdvertisedAddress() string {
+	namespace := os.Getenv("RUNTIME_NAMESPACE")
+	if namespace == "" {
+		return "localhost:9090"
+	}
+
+	return fmt.Sprintf("ocm-k8s-toolkit-artifact-service.%s.svc.cluster.local.", namespace)
+}
@@ -0,0 +1,144 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// gzipMagic are the first bytes of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Archive writes the blob as gzip compressed tarball, the format Flux-style consumers fetch artifacts in. Tarballs,
+// e.g. of directory trees, are repackaged so that the archive contains their files. Other blobs are archived as a
+// single file with the name. The headers of the files are normalized, so that the same content results in the same
+// archive.
+func Archive(w io.Writer, name, mediaType string, blob io.Reader) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	var err error
+	if strings.Contains(mediaType, "tar") {
+		err = repackage(tw, blob)
+	} else {
+		err = addFile(tw, name, blob)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close tarball: %w", err)
+	}
+
+	if err := gw.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+
+	return nil
+}
+
+// addFile adds the blob as single file with the name to the tarball. The blob is buffered in a temporary file, as the
+// size of the file must be known before it is written.
+func addFile(tw *tar.Writer, name string, blob io.Reader) error {
+	name = path.Base(path.Clean("/" + name))
+	if name == "/" {
+		return errors.New("blob must be archived with a file name")
+	}
+
+	tmp, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	size, err := io.Copy(tmp, blob)
+	if err != nil {
+		return fmt.Errorf("failed to read blob: %w", err)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind temporary file: %w", err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     size,
+	}); err != nil {
+		return fmt.Errorf("failed to write header of %s: %w", name, err)
+	}
+
+	if _, err := io.Copy(tw, tmp); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// repackage adds the directories and regular files of the (gzip compressed) tarball to the tarball. Other entries,
+// such as links, and entries outside the root of the tarball are skipped.
+func repackage(tw *tar.Writer, blob io.Reader) error {
+	reader := bufio.NewReader(blob)
+	if magic, err := reader.Peek(len(gzipMagic)); err == nil && bytes.Equal(magic, gzipMagic) {
+		gr, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("failed to decompress tarball: %w", err)
+		}
+		defer gr.Close()
+
+		return repackageTar(tw, gr)
+	}
+
+	return repackageTar(tw, reader)
+}
+
+func repackageTar(tw *tar.Writer, r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read tarball: %w", err)
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: 0o755}); err != nil {
+				return fmt.Errorf("failed to write header of %s: %w", name, err)
+			}
+		case tar.TypeReg:
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     name,
+				Mode:     header.Mode & 0o755,
+				Size:     header.Size,
+			}); err != nil {
+				return fmt.Errorf("failed to write header of %s: %w", name, err)
+			}
+
+			if _, err := io.Copy(tw, tr); err != nil {
+				return fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+	}
+}
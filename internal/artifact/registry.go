@@ -0,0 +1,215 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// ContentMediaType is the media type of the layer of the pushed artifacts. It is the media type of the content of
+	// Flux OCI artifacts, so that Flux OCIRepositories extract the archive.
+	ContentMediaType types.MediaType = "application/vnd.cncf.flux.content.v1.tar+gzip"
+	// ConfigMediaType is the media type of the config of the pushed artifacts.
+	ConfigMediaType types.MediaType = "application/vnd.cncf.flux.config.v1+json"
+
+	// maxTagLength is the maximum length of an OCI tag.
+	maxTagLength = 128
+)
+
+// invalidTagChars matches the characters that are not allowed in an OCI tag.
+var invalidTagChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// Registry pushes artifacts as single layer OCI artifacts to an OCI registry, e.g. a registry running in the cluster.
+// The artifacts are pushed in the format of Flux OCI artifacts. The artifacts are pushed anonymously and the artifacts
+// of previous revisions must be deleted with Delete.
+type Registry struct {
+	// Address is the host (and port) of the registry.
+	Address string
+	// Insecure allows to push to the registry via plain HTTP.
+	Insecure bool
+}
+
+// Push archives the blob with the file name (see Archive) and pushes it as single layer OCI artifact to the repository
+// of the object, tagged with the revision. It returns the reference of the pushed artifact.
+func (r *Registry) Push(
+	ctx context.Context,
+	obj Object,
+	revision, fileName, mediaType string,
+	blob io.Reader,
+) (*v1alpha1.SourceReference, error) {
+	tmp, err := os.CreateTemp("", "artifact-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary artifact file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := Archive(tmp, fileName, mediaType, blob); err != nil {
+		return nil, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write artifact file: %w", err)
+	}
+
+	layer, err := tarball.LayerFromFile(tmp.Name(), tarball.WithMediaType(ContentMediaType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create layer: %w", err)
+	}
+
+	img, err := mutate.Append(
+		mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), ConfigMediaType),
+		mutate.Addendum{Layer: layer},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact: %w", err)
+	}
+
+	annotated, ok := mutate.Annotations(img, map[string]string{
+		"org.opencontainers.image.revision": revision,
+		"org.opencontainers.image.title":    fileName,
+	}).(v1.Image)
+	if !ok {
+		return nil, fmt.Errorf("failed to annotate artifact")
+	}
+
+	repository := r.repository(obj)
+	tag := ociTag(revision)
+
+	ref, err := r.reference(repository, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := remote.Write(ref, annotated, remote.WithContext(ctx)); err != nil {
+		return nil, fmt.Errorf("failed to push artifact %s: %w", ref.String(), err)
+	}
+
+	digest, err := annotated.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest of artifact: %w", err)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   r.Address,
+		Repository: repository,
+		Reference:  fmt.Sprintf("%s@%s", tag, digest.String()),
+		Tag:        tag,
+		Digest:     digest.String(),
+	}, nil
+}
+
+// Exists returns true if the artifact the reference points to was pushed for the revision of the object and is still
+// tagged with the revision in the registry.
+func (r *Registry) Exists(ctx context.Context, obj Object, revision string, pushed *v1alpha1.SourceReference) (bool, error) {
+	repository := r.repository(obj)
+	tag := ociTag(revision)
+	if pushed.Registry != r.Address || pushed.Repository != repository || pushed.Tag != tag {
+		return false, nil
+	}
+
+	ref, err := r.reference(repository, tag)
+	if err != nil {
+		return false, err
+	}
+
+	desc, err := remote.Head(ref, remote.WithContext(ctx))
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get artifact %s: %w", ref.String(), err)
+	}
+
+	return desc.Digest.String() == pushed.Digest, nil
+}
+
+// Delete deletes the pushed artifact the reference points to from the registry. References that do not point to the
+// repository of the object, e.g. the source references of resources that were not pushed, are ignored. As the artifact
+// is deleted by its digest, the registry must allow deleting manifests.
+func (r *Registry) Delete(ctx context.Context, obj Object, pushed *v1alpha1.SourceReference) error {
+	if pushed == nil || pushed.Registry != r.Address || pushed.Repository != r.repository(obj) || pushed.Digest == "" {
+		return nil
+	}
+
+	ref, err := name.NewDigest(fmt.Sprintf("%s/%s@%s", r.Address, pushed.Repository, pushed.Digest), r.nameOptions()...)
+	if err != nil {
+		return fmt.Errorf("failed to parse artifact reference: %w", err)
+	}
+
+	if err := remote.Delete(ref, remote.WithContext(ctx)); err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("failed to delete artifact %s: %w", ref.String(), err)
+	}
+
+	return nil
+}
+
+// repository returns the repository the artifacts of the object are pushed to.
+func (r *Registry) repository(obj Object) string {
+	return path.Join(strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName())
+}
+
+// reference returns the reference of the tag in the repository of the registry.
+func (r *Registry) reference(repository, tag string) (name.Reference, error) {
+	ref, err := name.ParseReference(fmt.Sprintf("%s/%s:%s", r.Address, repository, tag), r.nameOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse artifact reference: %w", err)
+	}
+
+	return ref, nil
+}
+
+// nameOptions returns the options to parse the references of the registry.
+func (r *Registry) nameOptions() []name.Option {
+	if r.Insecure {
+		return []name.Option{name.Insecure}
+	}
+
+	return nil
+}
+
+// ociTag returns the revision as valid OCI tag. Like Helm, the '+' of semantic versions is replaced by '_'.
+func ociTag(revision string) string {
+	if revision == "" {
+		return "latest"
+	}
+
+	tag := invalidTagChars.ReplaceAllString(strings.ReplaceAll(revision, "+", "_"), "-")
+	if strings.HasPrefix(tag, ".") || strings.HasPrefix(tag, "-") {
+		tag = "_" + tag
+	}
+
+	if len(tag) > maxTagLength {
+		tag = tag[:maxTagLength]
+	}
+
+	return tag
+}
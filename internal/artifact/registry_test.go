@@ -0,0 +1,111 @@
+package artifact_test
+
+import (
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	. "github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
+)
+
+var _ = Describe("Registry", func() {
+	var (
+		reg      *Registry
+		resource *v1alpha1.Resource
+	)
+
+	BeforeEach(func() {
+		server := httptest.NewServer(registry.New())
+		DeferCleanup(server.Close)
+
+		reg = &Registry{Address: strings.TrimPrefix(server.URL, "http://"), Insecure: true}
+		resource = &v1alpha1.Resource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"}}
+	})
+
+	It("pushes a blob as single layer Flux artifact", func(ctx SpecContext) {
+		ref, err := reg.Push(ctx, resource, "1.0.0+build.1", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref.Registry).To(Equal(reg.Address))
+		Expect(ref.Repository).To(Equal("resource/default/config"))
+		Expect(ref.Tag).To(Equal("1.0.0_build.1"))
+		Expect(ref.Reference).To(Equal(ref.Tag + "@" + ref.Digest))
+
+		pushed, err := name.ParseReference(ref.Registry+"/"+ref.Repository+":"+ref.Tag, name.Insecure)
+		Expect(err).NotTo(HaveOccurred())
+		img, err := remote.Image(pushed)
+		Expect(err).NotTo(HaveOccurred())
+
+		digest, err := img.Digest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest.String()).To(Equal(ref.Digest))
+
+		manifest, err := img.Manifest()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest.Config.MediaType).To(Equal(ConfigMediaType))
+		Expect(manifest.Layers).To(HaveLen(1))
+		Expect(manifest.Layers[0].MediaType).To(Equal(ContentMediaType))
+		Expect(manifest.Annotations).To(HaveKeyWithValue("org.opencontainers.image.revision", "1.0.0+build.1"))
+	})
+
+	It("checks whether a pushed artifact still exists", func(ctx SpecContext) {
+		ref, err := reg.Push(ctx, resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Exists(ctx, resource, "1.0.0", ref)).To(BeTrue())
+
+		By("checking an artifact of another revision")
+		Expect(reg.Exists(ctx, resource, "1.1.0", ref)).To(BeFalse())
+
+		By("checking an artifact that was overwritten")
+		_, err = reg.Push(ctx, resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello Universe!"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reg.Exists(ctx, resource, "1.0.0", ref)).To(BeFalse())
+
+		By("checking an artifact that does not exist")
+		other := &v1alpha1.Resource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+		Expect(reg.Exists(ctx, other, "1.0.0", &v1alpha1.SourceReference{
+			Registry:   reg.Address,
+			Repository: "resource/default/other",
+			Tag:        "1.0.0",
+			Digest:     ref.Digest,
+		})).To(BeFalse())
+	})
+
+	It("deletes a pushed artifact", func(ctx SpecContext) {
+		ref, err := reg.Push(ctx, resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+
+		pushed, err := name.NewDigest(ref.Registry+"/"+ref.Repository+"@"+ref.Digest, name.Insecure)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = remote.Head(pushed, remote.WithContext(ctx))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(reg.Delete(ctx, resource, ref)).To(Succeed())
+		_, err = remote.Head(pushed, remote.WithContext(ctx))
+		Expect(err).To(HaveOccurred())
+
+		By("deleting an artifact that does not exist")
+		Expect(reg.Delete(ctx, resource, ref)).To(Succeed())
+	})
+
+	It("ignores references that were not pushed to the repository of the object", func(ctx SpecContext) {
+		ref, err := reg.Push(ctx, resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+
+		other := &v1alpha1.Resource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+		Expect(reg.Delete(ctx, other, ref)).To(Succeed())
+		Expect(reg.Delete(ctx, resource, &v1alpha1.SourceReference{
+			Registry:   "ghcr.io",
+			Repository: ref.Repository,
+			Digest:     ref.Digest,
+		})).To(Succeed())
+		Expect(reg.Delete(ctx, resource, nil)).To(Succeed())
+		Expect(reg.Exists(ctx, resource, "1.0.0", ref)).To(BeTrue())
+	})
+})
@@ -0,0 +1,142 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// shutdownTimeout is the time the artifact server waits for running downloads when it is stopped.
+const shutdownTimeout = 10 * time.Second
+
+// ServingLabel labels the pod whose artifact server serves the artifacts. The artifact service selects the pod by
+// this label, so that the artifacts are only downloaded from the leader.
+const ServingLabel = "delivery.ocm.software/artifact-server"
+
+// Server serves the artifacts of a storage over HTTP. Only the leader of the managers stores artifacts, so the server
+// only serves once its manager is elected leader and labels its pod with ServingLabel while it serves.
+// The server has no authentication: every client that reaches it, e.g. every pod in the cluster, can download the
+// artifacts. Access to the server must be restricted by network policies if required.
+type Server struct {
+	// Storage is the storage whose artifacts are served.
+	Storage *Storage
+	// Address is the address the server listens on.
+	Address string
+	// Elected is closed once the manager is elected leader (see manager.Manager.Elected).
+	Elected <-chan struct{}
+	// Client patches the labels of the pod.
+	Client client.Client
+	// Pod is the pod the server runs in. If the name is empty, no pod is labeled.
+	Pod types.NamespacedName
+}
+
+var (
+	_ manager.Runnable               = (*Server)(nil)
+	_ manager.LeaderElectionRunnable = (*Server)(nil)
+)
+
+// NeedLeaderElection returns false, as the server must remove the label of its pod before it is elected leader. The
+// label remains if the manager was restarted without stopping the server, e.g. when the manager crashed.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the artifacts from the time the manager is elected leader until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.label(ctx, false); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-s.Elected:
+	}
+
+	files := http.FileServer(http.Dir(s.Storage.BasePath))
+	server := &http.Server{
+		// Directories are not listed, as the names of the objects of all namespaces would be exposed.
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/") {
+				http.NotFound(w, r)
+
+				return
+			}
+			files.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// The pod is only labeled once the server listens, so that the service does not route to it before
+	listener, err := net.Listen("tcp", s.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.Address, err)
+	}
+
+	if err := s.label(ctx, true); err != nil {
+		_ = listener.Close()
+
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.label(shutdownCtx, false); err != nil {
+			log.FromContext(ctx).Error(err, "failed to remove label of artifact server")
+		}
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.FromContext(ctx).Error(err, "failed to shut down artifact server")
+		}
+	}()
+
+	log.FromContext(ctx).Info("starting artifact server", "address", s.Address)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve artifacts: %w", err)
+	}
+
+	return nil
+}
+
+// label adds or removes the ServingLabel of the pod the server runs in.
+func (s *Server) label(ctx context.Context, serving bool) error {
+	if s.Pod.Name == "" {
+		return nil
+	}
+
+	var value any
+	if serving {
+		value = "true"
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"labels": map[string]any{ServingLabel: value}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal label patch: %w", err)
+	}
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: s.Pod.Namespace, Name: s.Pod.Name}}
+	if err := s.Client.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to label pod %s: %w", s.Pod.String(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package artifact_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
+)
+
+var _ = Describe("Server", func() {
+	It("labels its pod while it serves as leader", func(ctx SpecContext) {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "manager",
+			Labels:    map[string]string{ServingLabel: "true"},
+		}}
+		clnt := fake.NewClientBuilder().WithObjects(pod).Build()
+
+		storage, err := NewStorage(GinkgoT().TempDir(), "artifacts.example.com")
+		Expect(err).NotTo(HaveOccurred())
+
+		elected := make(chan struct{})
+		server := &Server{
+			Storage: storage,
+			Address: "127.0.0.1:0",
+			Elected: elected,
+			Client:  clnt,
+			Pod:     client.ObjectKeyFromObject(pod),
+		}
+		Expect(server.NeedLeaderElection()).To(BeFalse())
+
+		serverCtx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+		done := make(chan error)
+		go func() {
+			done <- server.Start(serverCtx)
+		}()
+
+		labels := func(g Gomega) map[string]string {
+			current := &corev1.Pod{}
+			g.Expect(clnt.Get(ctx, client.ObjectKeyFromObject(pod), current)).To(Succeed())
+
+			return current.GetLabels()
+		}
+
+		By("removing the label of a pod that is not the leader")
+		Eventually(labels).Should(Not(HaveKey(ServingLabel)))
+
+		By("labeling the pod of the leader")
+		close(elected)
+		Eventually(labels).Should(HaveKeyWithValue(ServingLabel, "true"))
+
+		By("removing the label when the server is stopped")
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(labels(Default)).NotTo(HaveKey(ServingLabel))
+	})
+})
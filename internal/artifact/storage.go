@@ -0,0 +1,153 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// artifactExtension is the extension of the stored artifact files.
+const artifactExtension = ".tar.gz"
+
+// Object is an object artifacts are stored for.
+type Object interface {
+	GetKind() string
+	GetNamespace() string
+	GetName() string
+}
+
+// Storage stores artifacts in a directory that is served by the artifact server. The artifacts of an object are stored
+// by their digest in a directory of the object.
+type Storage struct {
+	// BasePath is the directory the artifacts are stored in.
+	BasePath string
+	// Hostname is the address the artifact server is reachable at by consumers, e.g. the address of its service.
+	Hostname string
+}
+
+// NewStorage returns a storage for the directory that creates the URLs of the artifacts with the hostname.
+func NewStorage(basePath, hostname string) (*Storage, error) {
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact storage directory: %w", err)
+	}
+
+	return &Storage{BasePath: basePath, Hostname: hostname}, nil
+}
+
+// Store archives the blob as artifact of the object (see Archive) and returns the artifact. Artifacts are stored by
+// their digest, storing the same content again returns the existing artifact file.
+func (s *Storage) Store(obj Object, revision, name, mediaType string, blob io.Reader) (*v1alpha1.Artifact, error) {
+	dir := filepath.Join(s.BasePath, s.objectPath(obj))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary artifact file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	if err := Archive(io.MultiWriter(tmp, hash), name, mediaType, blob); err != nil {
+		return nil, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write artifact file: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	file := filepath.Join(dir, checksum+artifactExtension)
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return nil, fmt.Errorf("failed to store artifact file: %w", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat artifact file: %w", err)
+	}
+	size := info.Size()
+
+	return &v1alpha1.Artifact{
+		URL:            s.url(path.Join(s.objectPath(obj), checksum+artifactExtension)),
+		Revision:       revision,
+		Digest:         "sha256:" + checksum,
+		Size:           &size,
+		LastUpdateTime: metav1.Now(),
+	}, nil
+}
+
+// Exists returns true if the file of the artifact of the object exists.
+func (s *Storage) Exists(obj Object, stored *v1alpha1.Artifact) bool {
+	file := strings.TrimPrefix(stored.Digest, "sha256:") + artifactExtension
+	info, err := os.Stat(filepath.Join(s.BasePath, s.objectPath(obj), file))
+
+	return err == nil && info.Mode().IsRegular()
+}
+
+// GarbageCollect deletes all artifacts of the object except for the artifact to keep.
+func (s *Storage) GarbageCollect(obj Object, keep *v1alpha1.Artifact) error {
+	dir := filepath.Join(s.BasePath, s.objectPath(obj))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read artifact directory: %w", err)
+	}
+
+	var keepFile string
+	if keep != nil {
+		keepFile = strings.TrimPrefix(keep.Digest, "sha256:") + artifactExtension
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == keepFile || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to delete stale artifact %s: %w", entry.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Remove deletes all artifacts of the object.
+func (s *Storage) Remove(obj Object) error {
+	if err := os.RemoveAll(filepath.Join(s.BasePath, s.objectPath(obj))); err != nil {
+		return fmt.Errorf("failed to delete artifacts: %w", err)
+	}
+
+	return nil
+}
+
+// objectPath returns the path of the directory of the artifacts of the object relative to the base path.
+func (s *Storage) objectPath(obj Object) string {
+	return path.Join(strings.ToLower(obj.GetKind()), obj.GetNamespace(), obj.GetName())
+}
+
+// url returns the URL the artifact file with the path relative to the base path is served at.
+func (s *Storage) url(filePath string) string {
+	u := url.URL{Scheme: "http", Host: s.Hostname, Path: "/" + filePath}
+
+	return u.String()
+}
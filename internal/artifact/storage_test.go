@@ -0,0 +1,169 @@
+package artifact_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	. "github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
+)
+
+// files returns the contents of the regular files of the gzip compressed tarball by their name.
+func files(archive []byte) map[string]string {
+	GinkgoHelper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	Expect(err).NotTo(HaveOccurred())
+
+	result := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		if header.Typeflag == tar.TypeReg {
+			data, err := io.ReadAll(tr)
+			Expect(err).NotTo(HaveOccurred())
+			result[header.Name] = string(data)
+		}
+	}
+}
+
+// tarball returns a tarball with the files and a link.
+func tarball(content map[string]string) []byte {
+	GinkgoHelper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, data := range content {
+		Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(data))})).To(Succeed())
+		_, err := tw.Write([]byte(data))
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "link", Linkname: "/etc/passwd"})).To(Succeed())
+	Expect(tw.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+var _ = Describe("Archive", func() {
+	It("archives a blob as single file", func() {
+		var buf bytes.Buffer
+		Expect(Archive(&buf, "config", "text/plain", strings.NewReader("Hello World!"))).To(Succeed())
+		Expect(files(buf.Bytes())).To(Equal(map[string]string{"config": "Hello World!"}))
+	})
+
+	It("repackages the files of a tarball", func() {
+		var buf bytes.Buffer
+		blob := tarball(map[string]string{"manifests/deployment.yaml": "kind: Deployment", "../escape": "nope"})
+		Expect(Archive(&buf, "manifests", "application/x-tar", bytes.NewReader(blob))).To(Succeed())
+		Expect(files(buf.Bytes())).To(Equal(map[string]string{"manifests/deployment.yaml": "kind: Deployment"}))
+	})
+
+	It("repackages the files of a gzip compressed tarball", func() {
+		var compressed bytes.Buffer
+		gw := gzip.NewWriter(&compressed)
+		_, err := gw.Write(tarball(map[string]string{"values.yaml": "replicas: 1"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gw.Close()).To(Succeed())
+
+		var buf bytes.Buffer
+		Expect(Archive(&buf, "chart", "application/x-tar+gzip", &compressed)).To(Succeed())
+		Expect(files(buf.Bytes())).To(Equal(map[string]string{"values.yaml": "replicas: 1"}))
+	})
+
+	It("creates the same archive for the same content", func() {
+		var first, second bytes.Buffer
+		Expect(Archive(&first, "config", "text/plain", strings.NewReader("Hello World!"))).To(Succeed())
+		Expect(Archive(&second, "config", "text/plain", strings.NewReader("Hello World!"))).To(Succeed())
+		Expect(first.Bytes()).To(Equal(second.Bytes()))
+	})
+})
+
+var _ = Describe("Storage", func() {
+	var (
+		storage  *Storage
+		resource *v1alpha1.Resource
+	)
+
+	BeforeEach(func() {
+		var err error
+		storage, err = NewStorage(GinkgoT().TempDir(), "artifacts.example.com")
+		Expect(err).NotTo(HaveOccurred())
+
+		resource = &v1alpha1.Resource{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"}}
+	})
+
+	// stored returns the content of the stored artifact file.
+	stored := func(artifact *v1alpha1.Artifact) []byte {
+		GinkgoHelper()
+
+		name := strings.TrimPrefix(artifact.Digest, "sha256:") + ".tar.gz"
+		data, err := os.ReadFile(filepath.Join(storage.BasePath, "resource", "default", "config", name))
+		Expect(err).NotTo(HaveOccurred())
+
+		return data
+	}
+
+	It("stores a blob by its digest", func() {
+		artifact, err := storage.Store(resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+
+		data := stored(artifact)
+		sum := sha256.Sum256(data)
+		Expect(artifact.Digest).To(Equal("sha256:" + hex.EncodeToString(sum[:])))
+		Expect(artifact.URL).To(Equal("http://artifacts.example.com/resource/default/config/" +
+			hex.EncodeToString(sum[:]) + ".tar.gz"))
+		Expect(artifact.Revision).To(Equal("1.0.0"))
+		Expect(*artifact.Size).To(Equal(int64(len(data))))
+		Expect(files(data)).To(Equal(map[string]string{"config": "Hello World!"}))
+	})
+
+	It("deletes stale artifacts", func() {
+		old, err := storage.Store(resource, "1.0.0", "config", "text/plain", strings.NewReader("old"))
+		Expect(err).NotTo(HaveOccurred())
+		current, err := storage.Store(resource, "1.1.0", "config", "text/plain", strings.NewReader("new"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(current.Digest).NotTo(Equal(old.Digest))
+
+		Expect(storage.GarbageCollect(resource, current)).To(Succeed())
+
+		entries, err := os.ReadDir(filepath.Join(storage.BasePath, "resource", "default", "config"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(files(stored(current))).To(Equal(map[string]string{"config": "new"}))
+	})
+
+	It("checks whether the file of an artifact exists", func() {
+		artifact, err := storage.Store(resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(storage.Exists(resource, artifact)).To(BeTrue())
+
+		Expect(storage.GarbageCollect(resource, nil)).To(Succeed())
+		Expect(storage.Exists(resource, artifact)).To(BeFalse())
+	})
+
+	It("removes all artifacts of an object", func() {
+		_, err := storage.Store(resource, "1.0.0", "config", "text/plain", strings.NewReader("Hello World!"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(storage.Remove(resource)).To(Succeed())
+		Expect(filepath.Join(storage.BasePath, "resource", "default", "config")).NotTo(BeADirectory())
+		Expect(storage.GarbageCollect(resource, nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,103 @@
+package deployer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// helmChartContentMediaType is the media type of the layer containing the chart archive in a Helm OCI artifact.
+const helmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// chartArchive returns the Helm chart archive contained in the resource data. The data is either the chart archive
+// itself or an OCI artifact set, as downloaded for resources with ociArtifact access, with the chart archive as layer.
+func chartArchive(data []byte) ([]byte, error) {
+	blobs := map[string][]byte{}
+	err := walkTar(data, func(header *tar.Header, content io.Reader) (bool, error) {
+		// A chart archive contains the chart in a directory named like the chart.
+		if path.Base(header.Name) == "Chart.yaml" && strings.Count(path.Clean(header.Name), "/") == 1 {
+			blobs = nil
+
+			return true, nil
+		}
+
+		// An artifact set contains all manifests and layers as blobs named <algorithm>.<encoded>.
+		dir, file := path.Split(path.Clean(header.Name))
+		if path.Clean(dir) != "blobs" || header.Typeflag != tar.TypeReg {
+			return false, nil
+		}
+
+		blob, err := io.ReadAll(content)
+		if err != nil {
+			return false, fmt.Errorf("failed to read blob %s: %w", header.Name, err)
+		}
+		blobs[strings.Replace(file, ".", ":", 1)] = blob
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if blobs == nil {
+		return data, nil
+	}
+
+	for _, blob := range blobs {
+		var manifest struct {
+			Layers []struct {
+				MediaType string `json:"mediaType"`
+				Digest    string `json:"digest"`
+			} `json:"layers"`
+		}
+		if err := json.Unmarshal(blob, &manifest); err != nil {
+			continue
+		}
+
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != helmChartContentMediaType {
+				continue
+			}
+
+			chart, ok := blobs[layer.Digest]
+			if !ok {
+				return nil, fmt.Errorf("chart layer %s not found in artifact", layer.Digest)
+			}
+
+			return chart, nil
+		}
+	}
+
+	return nil, errors.New("resource contains neither a helm chart nor an OCI artifact with a helm chart")
+}
+
+// walkTar calls fn for every entry of the (optionally gzip compressed) tar archive until fn returns true.
+func walkTar(data []byte, fn func(header *tar.Header, content io.Reader) (bool, error)) error {
+	var reader io.Reader = bytes.NewReader(data)
+	if gzipReader, err := gzip.NewReader(bytes.NewReader(data)); err == nil {
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		done, err := fn(header, tarReader)
+		if err != nil || done {
+			return err
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package deployer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("chartArchive", func() {
+	chart := helmChart("podinfo", map[string]string{
+		"templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\n",
+	})
+
+	It("returns a chart archive as is", func() {
+		Expect(chartArchive(chart)).To(Equal(chart))
+	})
+
+	It("extracts the chart archive from an OCI artifact set", func() {
+		sum := sha256.Sum256(chart)
+		chartDigest := "sha256:" + hex.EncodeToString(sum[:])
+		manifest := fmt.Sprintf(`{"schemaVersion":2,"layers":[{"mediaType":%q,"digest":%q}]}`,
+			helmChartContentMediaType, chartDigest)
+		manifestSum := sha256.Sum256([]byte(manifest))
+
+		artifactSet := tarGz(map[string][]byte{
+			"index.json": []byte(`{"schemaVersion":2}`),
+			"blobs/sha256." + hex.EncodeToString(manifestSum[:]): []byte(manifest),
+			"blobs/sha256." + hex.EncodeToString(sum[:]):         chart,
+		})
+
+		Expect(chartArchive(artifactSet)).To(Equal(chart))
+	})
+
+	It("fails for archives without chart", func() {
+		_, err := chartArchive(tarGz(map[string][]byte{"manifest.yaml": []byte("kind: ConfigMap")}))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// helmChart returns a gzipped chart archive of a chart with the given name and files.
+func helmChart(name string, files map[string]string) []byte {
+	entries := map[string][]byte{
+		name + "/Chart.yaml": []byte(fmt.Sprintf("apiVersion: v2\nname: %s\nversion: 1.0.0\n", name)),
+	}
+	for file, content := range files {
+		entries[name+"/"+file] = []byte(content)
+	}
+
+	return tarGz(entries)
+}
+
+func tarGz(entries map[string][]byte) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range entries {
+		Expect(tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})).To(Succeed())
+		_, err := tarWriter.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+	}
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
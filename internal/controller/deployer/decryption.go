@@ -0,0 +1,266 @@
+package deployer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/keys"
+	"github.com/getsops/sops/v3/pgp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+
+	sopsage "github.com/getsops/sops/v3/age"
+	sopsyaml "github.com/getsops/sops/v3/stores/yaml"
+	corev1 "k8s.io/api/core/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// decryptedAnnotation marks the objects of decrypted documents, so that they are still known after the documents
+	// were built and patched. It is removed before the objects are applied.
+	decryptedAnnotation = "delivery.ocm.software/decrypted"
+
+	// ageKeyExtension is the extension of the keys of the decryption secret containing age identities.
+	ageKeyExtension = ".agekey"
+	// pgpKeyExtension is the extension of the keys of the decryption secret containing armored PGP private keys.
+	pgpKeyExtension = ".asc"
+)
+
+// decryptionKeys are the private keys to decrypt SOPS encrypted documents with. Only the keys of the decryption
+// secret are used, never keys of the environment of the controller.
+type decryptionKeys struct {
+	age       sopsage.ParsedIdentities
+	gnuPGHome pgp.GnuPGHome
+}
+
+// decryptionKeys returns the private keys of the decryption secret of the deployer. The keys must be cleaned up after
+// use.
+func (r *Reconciler) decryptionKeys(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (*decryptionKeys, error) {
+	k := &decryptionKeys{}
+	if deployer.Spec.Decryption == nil || deployer.Spec.Decryption.SecretRef == nil {
+		return k, nil
+	}
+
+	name := deployer.Spec.Decryption.SecretRef.Name
+	namespace, err := resourceNamespace(deployer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine namespace of the decryption secret: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get decryption secret %s/%s: %w", namespace, name, err)
+	}
+
+	for key, value := range secret.Data {
+		switch filepath.Ext(key) {
+		case ageKeyExtension:
+			if err := k.age.Import(string(value)); err != nil {
+				k.cleanup()
+
+				return nil, fmt.Errorf("failed to import age identities of key %s of secret %s/%s: %w", key, namespace, name, err)
+			}
+		case pgpKeyExtension:
+			if k.gnuPGHome == "" {
+				if k.gnuPGHome, err = pgp.NewGnuPGHome(); err != nil {
+					return nil, fmt.Errorf("failed to create GnuPG home: %w", err)
+				}
+			}
+
+			if err := k.gnuPGHome.Import(value); err != nil {
+				k.cleanup()
+
+				return nil, fmt.Errorf("failed to import PGP key %s of secret %s/%s: %w", key, namespace, name, err)
+			}
+		}
+	}
+
+	return k, nil
+}
+
+// cleanup removes the imported PGP keys.
+func (k *decryptionKeys) cleanup() {
+	if k.gnuPGHome != "" {
+		_ = k.gnuPGHome.Cleanup()
+	}
+}
+
+// decryptManifest decrypts the SOPS encrypted documents of the (multi-document) YAML manifest. The documents are
+// decrypted before they are decoded, as the integrity of a document is verified against the order of its fields. The
+// decrypted documents are marked with the decrypted annotation (see unmarkDecrypted). If no document is encrypted, the
+// manifest is returned unchanged.
+func decryptManifest(manifest []byte, k *decryptionKeys) ([]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+
+	var (
+		result    bytes.Buffer
+		decrypted bool
+	)
+	for i := 0; ; i++ {
+		doc, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("failed to read document %d: %w", i, err)
+		}
+
+		if isSOPSEncrypted(doc) {
+			if doc, err = k.decrypt(doc); err != nil {
+				return nil, fmt.Errorf("failed to decrypt document %d: %w", i, err)
+			}
+			if doc, err = markDecrypted(doc); err != nil {
+				return nil, fmt.Errorf("failed to mark document %d as decrypted: %w", i, err)
+			}
+			decrypted = true
+		}
+
+		result.WriteString("---\n")
+		result.Write(doc)
+	}
+
+	if !decrypted {
+		return manifest, nil
+	}
+
+	return result.Bytes(), nil
+}
+
+// markDecrypted adds the decrypted annotation to the decrypted document. Documents that are not objects, e.g.
+// kustomizations, are not marked.
+func markDecrypted(doc []byte) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(doc, &obj.Object); err != nil {
+		return nil, err
+	}
+
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetKind() == types.KustomizationKind {
+		return doc, nil
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[decryptedAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+
+	return yaml.Marshal(obj.Object)
+}
+
+// unmarkDecrypted removes the decrypted annotation from the objects and returns the references of the objects that
+// were decrypted, so that their data is never revealed.
+func unmarkDecrypted(objs []*unstructured.Unstructured) map[string]bool {
+	decrypted := map[string]bool{}
+	for _, obj := range objs {
+		annotations := obj.GetAnnotations()
+		if _, ok := annotations[decryptedAnnotation]; !ok {
+			continue
+		}
+
+		delete(annotations, decryptedAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+		decrypted[objectRef(obj)] = true
+	}
+
+	return decrypted
+}
+
+// isSOPSEncrypted returns true if the document contains SOPS metadata.
+func isSOPSEncrypted(doc []byte) bool {
+	var metadata struct {
+		SOPS map[string]any `json:"sops"`
+	}
+
+	return yaml.Unmarshal(doc, &metadata) == nil && metadata.SOPS != nil
+}
+
+// decrypt decrypts the SOPS encrypted YAML document and verifies its integrity. The YAML store is used directly
+// instead of the stores of the SOPS command, which pull in the dependencies of the SOPS CLI.
+func (k *decryptionKeys) decrypt(doc []byte) ([]byte, error) {
+	store := &sopsyaml.Store{}
+	tree, err := store.LoadEncryptedFile(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SOPS metadata: %w", err)
+	}
+
+	dataKey, err := k.dataKey(tree.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher := aes.NewCipher()
+	mac, err := tree.Decrypt(dataKey, cipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt values: %w", err)
+	}
+
+	originalMAC, err := cipher.Decrypt(tree.Metadata.MessageAuthenticationCode, dataKey,
+		tree.Metadata.LastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt MAC: %w", err)
+	}
+
+	if originalMAC != mac {
+		return nil, errors.New("failed to verify integrity: the document was modified after it was encrypted")
+	}
+
+	return store.EmitPlainFile(tree.Branches)
+}
+
+// dataKey decrypts the data key of the document with the first matching age or PGP key. Documents whose data key is
+// split into multiple key groups are not supported.
+func (k *decryptionKeys) dataKey(metadata sops.Metadata) ([]byte, error) {
+	if len(metadata.KeyGroups) != 1 {
+		return nil, fmt.Errorf("documents with %d key groups are not supported", len(metadata.KeyGroups))
+	}
+
+	var errs []error
+	for _, key := range metadata.KeyGroups[0] {
+		var masterKey keys.MasterKey
+		switch key := key.(type) {
+		case *sopsage.MasterKey:
+			if len(k.age) == 0 {
+				continue
+			}
+			k.age.ApplyToMasterKey(key)
+			masterKey = key
+		case *pgp.MasterKey:
+			if k.gnuPGHome == "" {
+				continue
+			}
+			k.gnuPGHome.ApplyToMasterKey(key)
+			masterKey = key
+		default:
+			continue
+		}
+
+		dataKey, err := masterKey.Decrypt()
+		if err == nil {
+			return dataKey, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("the decryption secret contains no age or PGP key the document is encrypted for")
+	}
+
+	return nil, fmt.Errorf("failed to decrypt data key: %w", errors.Join(errs...))
+}
@@ -0,0 +1,139 @@
+package deployer
+
+import (
+	"time"
+
+	"filippo.io/age"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	sopsage "github.com/getsops/sops/v3/age"
+	sopsyaml "github.com/getsops/sops/v3/stores/yaml"
+)
+
+var _ = Describe("decryptManifest", func() {
+	It("returns a manifest without encrypted documents unchanged", func() {
+		manifest := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: plain
+stringData:
+  password: not-encrypted
+`)
+
+		decrypted, err := decryptManifest(manifest, &decryptionKeys{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decrypted).To(Equal(manifest))
+	})
+
+	It("decrypts the encrypted documents", func() {
+		identity, err := age.GenerateX25519Identity()
+		Expect(err).NotTo(HaveOccurred())
+
+		manifest := append([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: plain\n---\n"), encryptDocument([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: encrypted
+stringData:
+  password: secret-value
+`), identity)...)
+
+		decrypted, err := decryptManifest(manifest, &decryptionKeys{age: sopsage.ParsedIdentities{identity}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(decrypted)).To(ContainSubstring("name: plain"))
+		Expect(string(decrypted)).To(ContainSubstring("password: secret-value"))
+		Expect(string(decrypted)).NotTo(ContainSubstring("sops:"))
+
+		objs, err := decodeManifest(decrypted)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(2))
+		Expect(objs[0].GetAnnotations()).NotTo(HaveKey(decryptedAnnotation))
+		Expect(objs[1].GetAnnotations()).To(HaveKey(decryptedAnnotation))
+	})
+
+	It("detects documents encrypted with SOPS", func() {
+		Expect(isSOPSEncrypted([]byte(`apiVersion: v1
+kind: Secret
+stringData:
+  password: ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]
+sops:
+  mac: ENC[AES256_GCM,data:abc,iv:def,tag:ghi,type:str]
+  version: 3.10.2
+`))).To(BeTrue())
+		Expect(isSOPSEncrypted([]byte(`apiVersion: v1
+kind: ConfigMap
+data:
+  sops: "not metadata"
+`))).To(BeFalse())
+		Expect(isSOPSEncrypted([]byte(`{"apiVersion": "v1", "kind": "ConfigMap"}`))).To(BeFalse())
+	})
+})
+
+var _ = Describe("unmarkDecrypted", func() {
+	It("removes the decrypted annotation and returns the decrypted objects", func() {
+		plain := &unstructured.Unstructured{}
+		plain.SetKind("ConfigMap")
+		plain.SetNamespace("default")
+		plain.SetName("plain")
+		plain.SetAnnotations(map[string]string{"team": "platform"})
+
+		decrypted := &unstructured.Unstructured{}
+		decrypted.SetKind("ConfigMap")
+		decrypted.SetNamespace("default")
+		decrypted.SetName("decrypted")
+		decrypted.SetAnnotations(map[string]string{decryptedAnnotation: "true"})
+
+		Expect(unmarkDecrypted([]*unstructured.Unstructured{plain, decrypted})).
+			To(Equal(map[string]bool{"ConfigMap/default/decrypted": true}))
+		Expect(plain.GetAnnotations()).To(Equal(map[string]string{"team": "platform"}))
+		Expect(decrypted.GetAnnotations()).To(BeEmpty())
+	})
+
+	It("does not mark documents that are not objects", func() {
+		doc := []byte("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\n")
+		Expect(markDecrypted(doc)).To(Equal(doc))
+	})
+})
+
+// encryptDocument encrypts the data and stringData of the YAML document with SOPS for the age identity.
+func encryptDocument(doc []byte, identity *age.X25519Identity) []byte {
+	GinkgoHelper()
+
+	store := &sopsyaml.Store{}
+	branches, err := store.LoadPlainFile(doc)
+	Expect(err).NotTo(HaveOccurred())
+
+	masterKey, err := sopsage.MasterKeyFromRecipient(identity.Recipient().String())
+	Expect(err).NotTo(HaveOccurred())
+
+	tree := sops.Tree{
+		Branches: branches,
+		Metadata: sops.Metadata{
+			KeyGroups:      []sops.KeyGroup{{masterKey}},
+			EncryptedRegex: "^(data|stringData)$",
+			Version:        "3.10.2",
+		},
+	}
+
+	dataKey, errs := tree.GenerateDataKey()
+	Expect(errs).To(BeEmpty())
+	cipher := aes.NewCipher()
+	mac, err := tree.Encrypt(dataKey, cipher)
+	Expect(err).NotTo(HaveOccurred())
+	tree.Metadata.LastModified = time.Now().UTC()
+	tree.Metadata.MessageAuthenticationCode, err = cipher.Encrypt(mac, dataKey, tree.Metadata.LastModified.Format(time.RFC3339))
+	Expect(err).NotTo(HaveOccurred())
+
+	encrypted, err := store.EmitEncryptedFile(tree)
+	Expect(err).NotTo(HaveOccurred())
+
+	return encrypted
+}
@@ -0,0 +1,266 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
+)
+
+// reconcileDelete cleans up the deployed objects according to the deletion policy of the deployer and removes the
+// finalizer afterwards.
+func (r *Reconciler) reconcileDelete(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(deployer, deliveryv1alpha1.DeployerFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// Without the kubeconfig or the service account, the objects can never be cleaned up. Instead of blocking the
+	// deletion forever, the objects are left behind.
+	missing, err := r.missingDeployIdentity(ctx, deployer)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DeletionFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	if missing != "" {
+		logger.Info("skipping cleanup of the deployed objects", "reason", missing)
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.CleanupSkippedReason,
+			fmt.Sprintf("skipped cleanup of the deployed objects as %s: objects owned by the deployer are garbage "+
+				"collected, all other objects are orphaned", missing))
+
+		return ctrl.Result{}, r.removeFinalizer(ctx, deployer)
+	}
+
+	clnt, err := r.deployClient(ctx, deployer)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DeletionFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	if deployer.Spec.DeletionPolicy == deliveryv1alpha1.DeletionPolicyWaitForInstances {
+		instances, err := listInstances(ctx, clnt, deployer.Status.Inventory)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DeletionFailedReason), err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		refs := make([]string, 0, len(instances))
+		for _, instance := range instances {
+			// The instance created by the deployer is deleted with the deployer
+			if owner := controllerOf(instance); owner != nil && owner.UID == deployer.GetUID() {
+				continue
+			}
+
+			refs = append(refs, objectRef(instance))
+		}
+
+		if len(refs) > 0 {
+			logger.Info("waiting for instances to be deleted", "instances", refs)
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.InstancesExistReason,
+				fmt.Sprintf("waiting for %d instances to be deleted: %s", len(refs), strings.Join(refs, ", ")))
+
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+	}
+
+	if deployer.Spec.DeletionPolicy == deliveryv1alpha1.DeletionPolicyOrphan {
+		if err := r.orphan(ctx, clnt, deployer, deployer.Status.Inventory); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DeletionFailedReason), err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to orphan objects: %w", err)
+		}
+	} else {
+		// Helm releases are not garbage collected with the deployer and must be uninstalled explicitly.
+		if deployer.Spec.Type == deliveryv1alpha1.DeployerTypeHelm || deployer.Status.Helm != nil {
+			if err := r.uninstallHelmRelease(ctx, deployer); err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DeletionFailedReason), err.Error())
+
+				return ctrl.Result{}, err
+			}
+		}
+
+		if err := r.prune(ctx, clnt, deployer, staleEntries(deployer.Status.Inventory, nil)); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DeletionFailedReason), err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to delete objects: %w", err)
+		}
+	}
+
+	return ctrl.Result{}, r.removeFinalizer(ctx, deployer)
+}
+
+// removeFinalizer removes the finalizer from the deployer, so that it is deleted.
+func (r *Reconciler) removeFinalizer(ctx context.Context, deployer *deliveryv1alpha1.Deployer) error {
+	if updated := controllerutil.RemoveFinalizer(deployer, deliveryv1alpha1.DeployerFinalizer); updated {
+		if err := r.Update(ctx, deployer); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DeletionFailedReason, err.Error())
+
+			return fmt.Errorf("failed to remove finalizer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// missingDeployIdentity returns why the deployed objects of the deployer cannot be accessed anymore, i.e. the
+// kubeconfig secret or the impersonated service account does not exist. It returns an empty string if they exist.
+func (r *Reconciler) missingDeployIdentity(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (string, error) {
+	if deployer.Spec.KubeConfig == nil && r.serviceAccountName(deployer) == "" {
+		return "", nil
+	}
+
+	namespace, err := resourceNamespace(deployer)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		key  client.ObjectKey
+		obj  client.Object
+		kind string
+	)
+	if deployer.Spec.KubeConfig != nil {
+		key = client.ObjectKey{Namespace: namespace, Name: deployer.Spec.KubeConfig.SecretRef.Name}
+		obj, kind = &corev1.Secret{}, "kubeconfig secret"
+	} else {
+		key = client.ObjectKey{Namespace: namespace, Name: r.serviceAccountName(deployer)}
+		obj, kind = &corev1.ServiceAccount{}, "service account"
+	}
+
+	if err := r.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("the %s %s does not exist", kind, key), nil
+		}
+
+		return "", fmt.Errorf("failed to get %s %s: %w", kind, key, err)
+	}
+
+	return "", nil
+}
+
+// orphan removes the deployer as owner from the objects of the given inventory entries, so that they are not garbage
+// collected together with the deployer. Objects that are not controlled by the deployer are skipped.
+func (r *Reconciler) orphan(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	entries []deliveryv1alpha1.InventoryEntry,
+) error {
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	for _, entry := range entries {
+		obj, err := controlledObject(ctx, clnt, deployer, entry)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		if obj == nil {
+			continue
+		}
+
+		patch := client.MergeFrom(obj.DeepCopy())
+		removeController(obj, deployer.GetUID())
+		if err := clnt.Patch(ctx, obj, patch); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to orphan %s: %w", objectRef(obj), err))
+
+			continue
+		}
+
+		logger.Info("orphaned object", "object", objectRef(obj))
+	}
+
+	return errors.Join(errs...)
+}
+
+// listInstances returns the instances of the custom resources that kro generated for the resource graph definitions of
+// the given inventory entries.
+func listInstances(
+	ctx context.Context,
+	clnt client.Client,
+	entries []deliveryv1alpha1.InventoryEntry,
+) ([]*unstructured.Unstructured, error) {
+	var instances []*unstructured.Unstructured
+	for _, entry := range entries {
+		if schema.FromAPIVersionAndKind(entry.APIVersion, entry.Kind).GroupKind() != rgdGroupKind {
+			continue
+		}
+
+		rgd := &krov1alpha1.ResourceGraphDefinition{}
+		if err := clnt.Get(ctx, client.ObjectKey{Name: entry.Name}, rgd); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get resource graph definition %s: %w", entry.Name, err)
+		}
+
+		gvk, ok := instanceGroupVersionKind(rgd)
+		if !ok {
+			continue
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := clnt.List(ctx, list); err != nil {
+			// The custom resource definition does not exist (anymore), so there cannot be any instances.
+			if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+				continue
+			}
+
+			// The role of the controller only covers the instances in the group of kro
+			if apierrors.IsForbidden(err) && gvk.Group != krov1alpha1.KRODomainName {
+				return nil, fmt.Errorf("failed to list instances of %s, the controller must be permitted to list the "+
+					"instances in group %s: %w", gvk.Kind, gvk.Group, err)
+			}
+
+			return nil, fmt.Errorf("failed to list instances of %s: %w", gvk.Kind, err)
+		}
+
+		for _, instance := range list.Items {
+			instances = append(instances, &instance)
+		}
+	}
+
+	return instances, nil
+}
+
+// instanceGroupVersionKind returns the group, version, and kind of the instances of the resource graph definition. It
+// returns false if the resource graph definition does not define a schema.
+func instanceGroupVersionKind(rgd *krov1alpha1.ResourceGraphDefinition) (schema.GroupVersionKind, bool) {
+	if rgd.Spec.Schema == nil || rgd.Spec.Schema.Kind == "" {
+		return schema.GroupVersionKind{}, false
+	}
+
+	group := rgd.Spec.Schema.Group
+	if group == "" {
+		group = krov1alpha1.KRODomainName
+	}
+
+	return schema.GroupVersionKind{
+		Group:   group,
+		Version: rgd.Spec.Schema.APIVersion,
+		Kind:    rgd.Spec.Schema.Kind,
+	}, true
+}
@@ -0,0 +1,93 @@
+package deployer
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
+)
+
+var _ = Describe("instanceGroupVersionKind", func() {
+	It("returns the kind of the instances in the group of the schema", func() {
+		rgd := &krov1alpha1.ResourceGraphDefinition{
+			Spec: krov1alpha1.ResourceGraphDefinitionSpec{
+				Schema: &krov1alpha1.Schema{Kind: "WebApp", APIVersion: "v1alpha1", Group: "example.com"},
+			},
+		}
+
+		gvk, ok := instanceGroupVersionKind(rgd)
+		Expect(ok).To(BeTrue())
+		Expect(gvk).To(Equal(schema.GroupVersionKind{Group: "example.com", Version: "v1alpha1", Kind: "WebApp"}))
+	})
+
+	It("defaults to the group of kro", func() {
+		rgd := &krov1alpha1.ResourceGraphDefinition{
+			Spec: krov1alpha1.ResourceGraphDefinitionSpec{
+				Schema: &krov1alpha1.Schema{Kind: "WebApp", APIVersion: "v1alpha1"},
+			},
+		}
+
+		gvk, ok := instanceGroupVersionKind(rgd)
+		Expect(ok).To(BeTrue())
+		Expect(gvk.Group).To(Equal(krov1alpha1.KRODomainName))
+	})
+
+	It("returns false without a schema", func() {
+		_, ok := instanceGroupVersionKind(&krov1alpha1.ResourceGraphDefinition{})
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("missingDeployIdentity", func() {
+	deployer := func(serviceAccountName string, kubeConfig *v1alpha1.KubeConfigReference) *v1alpha1.Deployer {
+		return &v1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer"},
+			Spec: v1alpha1.DeployerSpec{
+				ResourceRef:        v1alpha1.ObjectKey{Name: "resource", Namespace: "default"},
+				ServiceAccountName: serviceAccountName,
+				KubeConfig:         kubeConfig,
+			},
+		}
+	}
+
+	It("reports a missing service account until it exists", func(ctx SpecContext) {
+		reconciler := &Reconciler{BaseReconciler: &ocm.BaseReconciler{Client: k8sClient}}
+
+		missing, err := reconciler.missingDeployIdentity(ctx, deployer("missing-identity", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(Equal("the service account default/missing-identity does not exist"))
+
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "missing-identity", Namespace: "default"}}
+		Expect(k8sClient.Create(ctx, serviceAccount)).To(Succeed())
+		DeferCleanup(func(ctx SpecContext) {
+			Expect(k8sClient.Delete(ctx, serviceAccount)).To(Succeed())
+		})
+
+		missing, err = reconciler.missingDeployIdentity(ctx, deployer("missing-identity", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+
+	It("reports a missing kubeconfig secret", func(ctx SpecContext) {
+		reconciler := &Reconciler{BaseReconciler: &ocm.BaseReconciler{Client: k8sClient}}
+
+		missing, err := reconciler.missingDeployIdentity(ctx, deployer("", &v1alpha1.KubeConfigReference{
+			SecretRef: meta.SecretKeyReference{Name: "missing-kubeconfig"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(Equal("the kubeconfig secret default/missing-kubeconfig does not exist"))
+	})
+
+	It("reports nothing for deployers with the identity of the controller", func(ctx SpecContext) {
+		missing, err := (&Reconciler{}).missingDeployIdentity(ctx, deployer("", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(missing).To(BeEmpty())
+	})
+})
@@ -0,0 +1,106 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// notReadyDependencies returns the dependencies of the deployer that do not exist or are not ready at their current
+// generation.
+func (r *Reconciler) notReadyDependencies(ctx context.Context, deployer *deliveryv1alpha1.Deployer) ([]string, error) {
+	var notReady []string
+	for _, ref := range deployer.Spec.DependsOn {
+		dependency := &deliveryv1alpha1.Deployer{}
+		if err := r.Get(ctx, client.ObjectKey{Name: ref.Name}, dependency); err != nil {
+			if apierrors.IsNotFound(err) {
+				notReady = append(notReady, fmt.Sprintf("%s (not found)", ref.Name))
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get dependency %s: %w", ref.Name, err)
+		}
+
+		if !conditions.IsTrue(dependency, meta.ReadyCondition) ||
+			dependency.Status.ObservedGeneration != dependency.GetGeneration() {
+			notReady = append(notReady, ref.Name)
+		}
+	}
+
+	return notReady, nil
+}
+
+// dependencyCycle returns the names of the deployers that form a cycle of dependencies reachable from the deployer. If
+// the dependencies of the deployer are acyclic, no names are returned.
+func (r *Reconciler) dependencyCycle(ctx context.Context, deployer *deliveryv1alpha1.Deployer) ([]string, error) {
+	return findCycle(deployer.GetName(), func(name string) ([]string, error) {
+		dependency := deployer
+		if name != deployer.GetName() {
+			dependency = &deliveryv1alpha1.Deployer{}
+			if err := r.Get(ctx, client.ObjectKey{Name: name}, dependency); err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil, nil
+				}
+
+				return nil, fmt.Errorf("failed to get dependency %s: %w", name, err)
+			}
+		}
+
+		names := make([]string, 0, len(dependency.Spec.DependsOn))
+		for _, ref := range dependency.Spec.DependsOn {
+			names = append(names, ref.Name)
+		}
+
+		return names, nil
+	})
+}
+
+// findCycle searches the dependencies reachable from the start for a cycle and returns it, beginning and ending with
+// the same dependency. Cycles that do not pass through the start are returned as well, as the start would wait for
+// them forever. Every dependency is visited once.
+func findCycle(start string, dependsOn func(name string) ([]string, error)) ([]string, error) {
+	visited := map[string]bool{start: true}
+	// onPath maps the dependencies of the current path to their position in the path
+	onPath := map[string]int{}
+
+	var visit func(path []string) ([]string, error)
+	visit = func(path []string) ([]string, error) {
+		name := path[len(path)-1]
+		onPath[name] = len(path) - 1
+		defer delete(onPath, name)
+
+		dependencies, err := dependsOn(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dependency := range dependencies {
+			if i, ok := onPath[dependency]; ok {
+				return append(slices.Clone(path[i:]), dependency), nil
+			}
+
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+
+			cycle, err := visit(append(path, dependency))
+			if err != nil || cycle != nil {
+				return cycle, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	return visit([]string{start})
+}
@@ -0,0 +1,81 @@
+package deployer
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("findCycle", func() {
+	dependsOn := func(graph map[string][]string) func(string) ([]string, error) {
+		return func(name string) ([]string, error) {
+			return graph[name], nil
+		}
+	}
+
+	It("returns no cycle for acyclic dependencies", func() {
+		cycle, err := findCycle("a", dependsOn(map[string][]string{
+			"a": {"b", "c"},
+			"b": {"c"},
+			"c": {},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(BeEmpty())
+	})
+
+	It("returns the cycle through the start", func() {
+		cycle, err := findCycle("a", dependsOn(map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"a"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(Equal([]string{"a", "b", "c", "a"}))
+	})
+
+	It("returns a dependency on itself", func() {
+		cycle, err := findCycle("a", dependsOn(map[string][]string{"a": {"a"}}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(Equal([]string{"a", "a"}))
+	})
+
+	It("returns cycles that do not lead back to the start", func() {
+		cycle, err := findCycle("a", dependsOn(map[string][]string{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"b"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(Equal([]string{"b", "c", "b"}))
+	})
+
+	It("returns a cycle of two dependencies the start depends on", func() {
+		cycle, err := findCycle("d", dependsOn(map[string][]string{
+			"d": {"c", "a"},
+			"c": {},
+			"a": {"b"},
+			"b": {"a"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(Equal([]string{"a", "b", "a"}))
+	})
+
+	It("returns no cycle for dependencies that are reached on several paths", func() {
+		cycle, err := findCycle("a", dependsOn(map[string][]string{
+			"a": {"b", "c"},
+			"b": {"d"},
+			"c": {"d"},
+			"d": {},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cycle).To(BeEmpty())
+	})
+
+	It("returns errors of the lookup", func() {
+		_, err := findCycle("a", func(string) ([]string, error) {
+			return nil, errors.New("lookup failed")
+		})
+		Expect(err).To(MatchError("lookup failed"))
+	})
+})
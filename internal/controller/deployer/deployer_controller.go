@@ -4,12 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
 	"ocm.software/ocm/api/datacontext"
-	"ocm.software/ocm/api/ocm/compdesc"
 	"ocm.software/ocm/api/ocm/extensions/attrs/signingattr"
 	"ocm.software/ocm/api/ocm/tools/signing"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -19,22 +26,36 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/yaml"
 
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ocmctx "ocm.software/ocm/api/ocm"
-	v1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/event"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/util"
 )
 
+// fieldManager is the field manager used by the deployer to apply objects server-side.
+const fieldManager = "ocm-k8s-toolkit-deployer"
+
+// pollInterval is the interval in which the deployer checks again on objects it waits for.
+const pollInterval = 10 * time.Second
+
 // Reconciler reconciles a Deployer object.
 type Reconciler struct {
 	*ocm.BaseReconciler
+
+	// RESTConfig is used to create the clients for Helm releases.
+	RESTConfig *rest.Config
+
+	// DefaultServiceAccount is the name of the service account that is impersonated by Deployers that do not specify a
+	// service account. If empty, such Deployers deploy with the permissions of the controller.
+	DefaultServiceAccount string
 }
 
 var _ ocm.Reconciler = (*Reconciler)(nil)
@@ -42,7 +63,10 @@ var _ ocm.Reconciler = (*Reconciler)(nil)
 // +kubebuilder:rbac:groups=delivery.ocm.software,resources=deployers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=delivery.ocm.software,resources=deployers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=delivery.ocm.software,resources=deployers/finalizers,verbs=update
-// +kubebuilder:rbac:groups=kro.run,resources=resourcegraphdefinitions,verbs=list;watch;create;update;patch
+// +kubebuilder:rbac:groups=kro.run,resources=resourcegraphdefinitions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kro.run,resources=*,verbs=get;list;create;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
@@ -58,11 +82,40 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 				return nil
 			}
 
-			return []string{fmt.Sprintf(
-				"%s/%s",
-				deployer.Spec.ResourceRef.Namespace,
-				deployer.Spec.ResourceRef.Name,
-			)}
+			resources := deployer.GetResources()
+			keys := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				keys = append(keys, fmt.Sprintf(
+					"%s/%s",
+					resource.ResourceRef.Namespace,
+					resource.ResourceRef.Name,
+				))
+			}
+
+			return keys
+		},
+	); err != nil {
+		return err
+	}
+
+	// Build index for deployers that depend on other deployers to get notified about changes of their dependencies.
+	const dependsOnField = ".spec.dependsOn"
+	if err := mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&deliveryv1alpha1.Deployer{},
+		dependsOnField,
+		func(obj client.Object) []string {
+			deployer, ok := obj.(*deliveryv1alpha1.Deployer)
+			if !ok {
+				return nil
+			}
+
+			names := make([]string, 0, len(deployer.Spec.DependsOn))
+			for _, ref := range deployer.Spec.DependsOn {
+				names = append(names, ref.Name)
+			}
+
+			return names
 		},
 	); err != nil {
 		return err
@@ -70,6 +123,8 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&deliveryv1alpha1.Deployer{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Watch the applied resource graph definitions to propagate their status to the deployer
+		Owns(&krov1alpha1.ResourceGraphDefinition{}).
 		// Watch for events from OCM resources that are referenced by the deployer
 		Watches(
 			&deliveryv1alpha1.Resource{},
@@ -100,6 +155,24 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 					})
 				}
 
+				return requests
+			})).
+		// Watch for status changes of deployers that other deployers depend on
+		Watches(
+			&deliveryv1alpha1.Deployer{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				list := &deliveryv1alpha1.DeployerList{}
+				if err := r.List(ctx, list, client.MatchingFields{dependsOnField: obj.GetName()}); err != nil {
+					return []reconcile.Request{}
+				}
+
+				requests := make([]reconcile.Request, 0, len(list.Items))
+				for _, deployer := range list.Items {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: deployer.GetName()},
+					})
+				}
+
 				return requests
 			})).
 		Complete(r)
@@ -117,15 +190,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	patchHelper := patch.NewSerialPatcher(deployer, r.Client)
 	defer func(ctx context.Context) {
-		err = status.UpdateStatus(ctx, patchHelper, deployer, r.EventRecorder, time.Second, err)
+		err = status.UpdateStatus(ctx, patchHelper, deployer, r.EventRecorder, deployer.GetRequeueAfter(), err)
 	}(ctx)
 
+	if !deployer.GetDeletionTimestamp().IsZero() {
+		return r.reconcileDelete(ctx, deployer)
+	}
+
 	if deployer.Spec.Suspend {
 		return ctrl.Result{}, nil
 	}
 
-	if !deployer.GetDeletionTimestamp().IsZero() {
-		return ctrl.Result{}, errors.New("deployer is being deleted")
+	// The finalizer makes sure that the deployed objects are cleaned up according to the deletion policy.
+	if updated := controllerutil.AddFinalizer(deployer, deliveryv1alpha1.DeployerFinalizer); updated {
+		if err := r.Update(ctx, deployer); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Wait for the deployers this deployer depends on, unless their dependencies form a cycle
+	if len(deployer.Spec.DependsOn) > 0 {
+		cycle, err := r.dependencyCycle(ctx, deployer)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DependencyNotReadyReason, err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		if len(cycle) > 0 {
+			// return no requeue as we watch the dependencies for changes anyway
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DependencyCycleReason,
+				fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> ")))
+
+			return ctrl.Result{}, nil
+		}
+
+		notReady, err := r.notReadyDependencies(ctx, deployer)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DependencyNotReadyReason, err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		if len(notReady) > 0 {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DependencyNotReadyReason,
+				fmt.Sprintf("waiting for dependencies to become ready: %s", strings.Join(notReady, ", ")))
+
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
 	}
 
 	octx := ocmctx.New(datacontext.MODE_EXTENDED)
@@ -151,123 +265,493 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, fmt.Errorf("failed to configure context: %w", err)
 	}
 
-	resourceNamespace := deployer.Spec.ResourceRef.Namespace
-	if resourceNamespace == "" {
-		resourceNamespace = deployer.GetNamespace()
+	resources := deployer.GetResources()
+	if deployer.Spec.Type == deliveryv1alpha1.DeployerTypeHelm && len(resources) != 1 {
+		status.MarkAsStalled(r.EventRecorder, deployer, deliveryv1alpha1.HelmReleaseFailedReason,
+			"deployers of type Helm must deploy exactly one resource")
+
+		return ctrl.Result{}, nil
 	}
 
-	resource, err := util.GetReadyObject[deliveryv1alpha1.Resource, *deliveryv1alpha1.Resource](ctx, r.Client, client.ObjectKey{
-		Namespace: resourceNamespace,
-		Name:      deployer.Spec.ResourceRef.Name,
-	})
-	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.ResourceIsNotAvailable, err.Error())
+	// Download all resources before deploying any of them to not start a rollout that cannot be completed.
+	revisions := make([]*resourceRevision, 0, len(resources))
+	for _, ref := range resources {
+		revision, err := r.fetchResource(ctx, octx, session, deployer, ref)
+		if err != nil {
+			if errors.Is(err, util.NotReadyError{}) || errors.Is(err, util.DeletionError{}) {
+				logger.Info("stop reconciling as the resource is not available", "error", err.Error())
+
+				// return no requeue as we watch the object for changes anyway
+				return ctrl.Result{}, nil
+			}
 
-		if errors.Is(err, util.NotReadyError{}) || errors.Is(err, util.DeletionError{}) {
-			logger.Info("stop reconciling as the resource is not available", "error", err.Error())
+			return ctrl.Result{}, err
+		}
 
-			// return no requeue as we watch the object for changes anyway
-			return ctrl.Result{}, nil
+		revisions = append(revisions, revision)
+	}
+
+	digest := revisionDigest(revisions)
+	version := revisionVersion(revisions)
+
+	if deployer.Spec.Type == deliveryv1alpha1.DeployerTypeHelm {
+		return r.reconcileHelmRelease(ctx, deployer, revisions[0])
+	}
+
+	// Do not retry a revision that was rolled back until the resources or the spec of the deployer change, but keep
+	// reconciling the restored revision
+	restored := isRolledBack(deployer, digest)
+	if restored {
+		logger.Info("reconciling restored revision instead of revision that was rolled back", "version", version)
+
+		if revisions, err = healthyRevisions(deployer); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.RollbackFailedReason, err.Error())
+
+			return ctrl.Result{}, err
 		}
 
-		return ctrl.Result{}, fmt.Errorf("failed to get ready resource: %w", err)
+		digest = deployer.Status.LastHealthyRevision.Digest
+		version = revisionVersion(revisions)
 	}
 
-	// Download the resource
-	spec, err := octx.RepositorySpecForConfig(resource.Status.Component.RepositorySpec.Raw, nil)
+	clnt, err := r.deployClient(ctx, deployer)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.CreateOrUpdateFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
 
-		return ctrl.Result{}, fmt.Errorf("failed to get repository spec: %w", err)
+	// The private keys are only read if the deployer decrypts its objects
+	var decryption *decryptionKeys
+	if deployer.Spec.Decryption != nil {
+		if decryption, err = r.decryptionKeys(ctx, deployer); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DecryptionFailedReason, err.Error())
+
+			return ctrl.Result{}, err
+		}
+		defer decryption.cleanup()
 	}
 
-	repo, err := session.LookupRepository(octx, spec)
+	for _, revision := range revisions {
+		// Keep the resource data before it is decrypted, so that the plaintext is never stored in the status
+		manifest := revision.data
+		revision.manifest = manifest
+
+		// Build the kustomization into the manifest to deploy. Its encrypted documents are decrypted before the build.
+		if deployer.Spec.Type == deliveryv1alpha1.DeployerTypeKustomize {
+			manifest, err = buildKustomization(manifest, ptr.Deref(deployer.Spec.Kustomize, deliveryv1alpha1.KustomizeSpec{}), decryption)
+			if err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.KustomizeBuildFailedReason, err.Error())
+
+				if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+					return rollbackResult(deployer, rollbackErr)
+				}
+
+				return ctrl.Result{}, err
+			}
+		}
+
+		// Decrypt the encrypted documents of the verified manifest
+		if decryption != nil && deployer.Spec.Type != deliveryv1alpha1.DeployerTypeKustomize {
+			manifest, err = decryptManifest(manifest, decryption)
+			if err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.DecryptionFailedReason, err.Error())
+
+				if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+					return rollbackResult(deployer, rollbackErr)
+				}
+
+				return ctrl.Result{}, fmt.Errorf("failed to decrypt manifest of resource %s: %w", revision.ResourceRef.Name, err)
+			}
+		}
+
+		// Unmarshal the manifest into the objects to deploy
+		revision.objects, err = decodeObjects(deployer.Spec.Type, manifest)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+			if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+				return rollbackResult(deployer, rollbackErr)
+			}
+
+			return ctrl.Result{}, fmt.Errorf("failed to unmarshal manifest of resource %s: %w", revision.ResourceRef.Name, err)
+		}
+
+		// Patch the verified objects with the environment specific changes of the deployer
+		if deployer.Spec.PostRender != nil {
+			revision.objects, err = postRender(revision.objects, *deployer.Spec.PostRender)
+			if err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.PostRenderFailedReason, err.Error())
+
+				if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+					return rollbackResult(deployer, rollbackErr)
+				}
+
+				return ctrl.Result{}, fmt.Errorf("failed to post-render objects of resource %s: %w", revision.ResourceRef.Name, err)
+			}
+		}
+	}
+
+	// TODO: Improve deployer maturity (@frewilhelm)
+	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/194 (@frewilhelm)
+	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/195 (@frewilhelm)
+	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/196 (@frewilhelm)
+
+	var objs []*unstructured.Unstructured
+	for _, revision := range revisions {
+		objs = append(objs, revision.objects...)
+	}
+
+	// Remember the objects of encrypted documents, as their data must not be revealed
+	decrypted := unmarkDecrypted(objs)
+
+	// The instance is created after kro accepted the resource graph definition, but its values are resolved upfront
+	instance, err := r.instanceObject(ctx, deployer, objs)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.GetValuesFailedReason), err.Error())
 
-		return ctrl.Result{}, fmt.Errorf("invalid repository spec: %w", err)
+		return ctrl.Result{}, err
 	}
 
-	cv, err := session.LookupComponentVersion(repo, resource.Status.Component.Component, resource.Status.Component.Version)
+	// Do not take over objects of other owners unless the deployer adopts them
+	conflicts, err := r.checkOwnership(ctx, clnt, deployer, objs)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.OwnershipConflictReason), err.Error())
 
-		return ctrl.Result{}, fmt.Errorf("failed to get component version: %w", err)
+		return ctrl.Result{}, err
 	}
 
-	resourceReference := v1.ResourceReference{
-		Resource:      resource.Spec.Resource.ByReference.Resource,
-		ReferencePath: resource.Spec.Resource.ByReference.ReferencePath,
+	if len(conflicts) > 0 {
+		err := fmt.Errorf("ownership conflicts: %s", strings.Join(conflicts, "; "))
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.OwnershipConflictReason, err.Error())
+
+		return ctrl.Result{}, err
 	}
 
-	a := cv.GetDescriptor()
-	resourceAccess, _, err := ocm.GetResourceAccessForComponentVersion(
-		ctx,
-		cv,
-		resourceReference,
-		&ocm.Descriptors{List: []*compdesc.ComponentDescriptor{a}},
-		resource.Spec.SkipVerify,
-	)
+	// Validate all objects before applying any of them to not partially apply an invalid revision
+	violations, err := r.validateObjects(ctx, clnt, deployer, objs)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.ValidationFailedReason), err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	if len(violations) > 0 {
+		err := fmt.Errorf("invalid objects: %s", strings.Join(violations, "; "))
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.ValidationFailedReason, err.Error())
+
+		if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+			return rollbackResult(deployer, rollbackErr)
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	// Preview the changes without applying them
+	if deployer.Spec.Mode == deliveryv1alpha1.DeployerModeDryRun {
+		summary, err := r.dryRun(ctx, clnt, deployer, objs, decrypted)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DryRunFailedReason), err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		summary.Digest = digest
+		deployer.Status.DryRun = summary
+		status.MarkReady(r.EventRecorder, deployer, "Dry-run of version %s: %d created, %d updated, %d unchanged, %d pruned",
+			version, len(summary.Created), len(summary.Updated), len(summary.Unchanged), len(summary.Pruned))
 
-		return ctrl.Result{}, fmt.Errorf("failed to get resource access: %w", err)
+		return ctrl.Result{RequeueAfter: deployer.GetRequeueAfter()}, nil
 	}
 
-	// Get the resource graph definition manifest and its digest. Compare the digest to the one in the resource to make
-	// sure the resource is up to date.
-	rgdManifest, digest, err := getResource(cv, resourceAccess)
+	// Only objects of a revision that was already applied can drift. Otherwise, the differences are expected changes.
+	patchedDigest, err := postRenderDigest(deployer, revisions)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.PostRenderFailedReason, err.Error())
 
-		return ctrl.Result{}, fmt.Errorf("failed to get resource graph definition manifest: %w", err)
+		return ctrl.Result{}, err
 	}
 
-	if resource.Status.Resource.Digest != digest {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, "resource digest mismatch")
+	revisionApplied := deployer.Status.LastAppliedDigest == digest && deployer.Status.LastAppliedPostRenderDigest == patchedDigest
+	if !revisionApplied || deployer.Status.ObservedGeneration != deployer.GetGeneration() {
+		deployer.Status.LastAppliedTime = &metav1.Time{Time: time.Now()}
+	}
 
-		return ctrl.Result{}, fmt.Errorf("resource digest mismatch: expected %s, got %s", resource.Status.Resource.Digest, digest)
+	deployer.Status.Resources = make([]deliveryv1alpha1.DeployedResourceStatus, 0, len(revisions))
+	for _, revision := range revisions {
+		deployer.Status.Resources = append(deployer.Status.Resources, resourceStatus(revision))
 	}
 
-	// Apply, Update, or Delete RGD
-	var rgd krov1alpha1.ResourceGraphDefinition
-	// Unmarshal the manifest into the ResourceGraphDefinition object
-	if err := yaml.Unmarshal(rgdManifest, &rgd); err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+	var applied []*unstructured.Unstructured
+	var drifted []string
+	for i, revision := range revisions {
+		resourceApplied := make([]*unstructured.Unstructured, 0, len(revision.objects))
+		for _, obj := range revision.objects {
+			force := deployer.Spec.Force
+			if revisionApplied {
+				live, drift, err := r.detectDrift(ctx, clnt, deployer, obj)
+				if err != nil {
+					status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.DriftDetectionFailedReason), err.Error())
+
+					return ctrl.Result{}, fmt.Errorf("failed to detect drift: %w", err)
+				}
+
+				if len(drift) > 0 {
+					logger.Info("detected drift", "object", objectRef(live), "fields", drift)
+					drifted = append(drifted, fmt.Sprintf("%s (%s)", objectRef(live), strings.Join(drift, ", ")))
 
-		return ctrl.Result{}, fmt.Errorf("failed to unmarshal manifest: %w", err)
+					// Keep the changes if the drift must not be reverted
+					if !deployer.Spec.RevertDrift {
+						resourceApplied = append(resourceApplied, live)
+
+						continue
+					}
+
+					force = true
+				}
+			}
+
+			// Apply the object server-side to only manage the fields that are part of the manifest
+			actual, err := r.applyObject(ctx, clnt, deployer, obj, force)
+			if err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.CreateOrUpdateFailedReason), err.Error())
+
+				if rolledBack, rollbackErr := r.rollback(ctx, clnt, deployer, digest, version, err); rolledBack || rollbackErr != nil {
+					return rollbackResult(deployer, rollbackErr)
+				}
+
+				return ctrl.Result{}, fmt.Errorf("failed to apply %s: %w", objectRef(obj), err)
+			}
+
+			logger.Info("applied object", "object", objectRef(actual))
+			resourceApplied = append(resourceApplied, actual)
+		}
+
+		applied = append(applied, resourceApplied...)
+		resourceState := &deployer.Status.Resources[i]
+
+		// Wait for the objects to become ready before deploying the next resource
+		if revision.WaitForReady {
+			notReady, err := notReadyObjects(ctx, clnt, resourceApplied)
+			if err != nil {
+				status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.ObjectsNotReadyReason), err.Error())
+
+				return ctrl.Result{}, fmt.Errorf("failed to check readiness: %w", err)
+			}
+
+			if len(notReady) > 0 {
+				msg := fmt.Sprintf("Waiting for %s to become ready", strings.Join(notReady, ", "))
+				resourceState.Message = msg
+
+				// Keep track of the applied objects without pruning any object of the previous revision, as the rollout
+				// is not complete yet.
+				inventory := newInventory(applied)
+				deployer.Status.Inventory = append(inventory, staleEntries(deployer.Status.Inventory, inventory)...)
+				status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.ObjectsNotReadyReason,
+					fmt.Sprintf("resource %s: %s", revision.ResourceRef.Name, msg))
+				logger.Info("waiting for objects to become ready", "resource", revision.ResourceRef.Name, "objects", notReady)
+
+				return ctrl.Result{RequeueAfter: pollInterval}, nil
+			}
+		}
+
+		resourceState.Ready = true
+		resourceState.Message = fmt.Sprintf("Applied version %s", revision.version)
 	}
 
-	// TODO: Improve deployer maturity (@frewilhelm)
-	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/194 (@frewilhelm)
-	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/195 (@frewilhelm)
-	//  - https://github.com/open-component-model/ocm-k8s-toolkit/issues/196 (@frewilhelm)
+	// Delete objects that were applied by the previous revision but are not part of the current one
+	inventory := newInventory(applied)
+	if instance != nil {
+		// The instance is the last entry, so that it is deleted before the resource graph definition it belongs to
+		inventory = append(inventory, inventoryEntry(instance))
+	}
+	if ptr.Deref(deployer.Spec.Prune, true) {
+		if err := r.prune(ctx, clnt, deployer, staleEntries(deployer.Status.Inventory, inventory)); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.PruneFailedReason), err.Error())
 
-	actual := rgd.DeepCopy()
+			return ctrl.Result{}, fmt.Errorf("failed to prune objects: %w", err)
+		}
+	}
 
-	// Create or update the object in the cluster
-	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, actual, func() error {
-		if err := controllerutil.SetControllerReference(deployer, &rgd, r.Scheme); err != nil {
-			return fmt.Errorf("failed to set controller reference on resource graph definition: %w", err)
+	deployer.Status.Inventory = inventory
+	deployer.Status.LastAppliedDigest = digest
+	deployer.Status.LastAppliedPostRenderDigest = patchedDigest
+	deployer.Status.DryRun = nil
+
+	switch {
+	case len(drifted) == 0:
+		conditions.Delete(deployer, deliveryv1alpha1.DriftedCondition)
+	case deployer.Spec.RevertDrift:
+		conditions.MarkFalse(deployer, deliveryv1alpha1.DriftedCondition, deliveryv1alpha1.DriftRevertedReason,
+			"Reverted drift of %s", strings.Join(drifted, "; "))
+		event.New(r.EventRecorder, deployer, nil, eventv1.EventSeverityInfo,
+			"Reverted drift of %s", strings.Join(drifted, "; "))
+	default:
+		conditions.MarkTrue(deployer, deliveryv1alpha1.DriftedCondition, deliveryv1alpha1.DriftDetectedReason,
+			"Detected drift of %s", strings.Join(drifted, "; "))
+	}
+
+	// Propagate the status of the applied resource graph definitions to the deployer
+	for _, obj := range applied {
+		if !isResourceGraphDefinitionType(deployer) || obj.GroupVersionKind().GroupKind() != rgdGroupKind {
+			continue
 		}
 
-		actual.Spec = rgd.Spec
+		rgd := &krov1alpha1.ResourceGraphDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rgd); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
 
-		return nil
-	})
+			return ctrl.Result{}, fmt.Errorf("failed to convert resource graph definition: %w", err)
+		}
+
+		if err := propagateResourceGraphDefinitionStatus(deployer, rgd); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.ResourceGraphDefinitionNotReadyReason, err.Error())
+			logger.Info("resource graph definition is not ready", "name", rgd.GetName(), "reason", err.Error())
+
+			// Resource graph definitions in remote clusters cannot be watched
+			if deployer.Spec.KubeConfig != nil {
+				return ctrl.Result{RequeueAfter: pollInterval}, nil
+			}
+
+			// return no requeue as we watch the owned resource graph definition for changes anyway
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Create the instance of the accepted resource graph definition and wait for kro to reconcile it
+	if instance != nil {
+		actual, err := r.applyObject(ctx, clnt, deployer, instance, deployer.Spec.Force)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.CreateOrUpdateFailedReason), err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to apply instance %s: %w", objectRef(instance), err)
+		}
+
+		logger.Info("applied instance", "instance", objectRef(actual))
+
+		ready, err := propagateInstanceStatus(ctx, clnt, deployer, actual)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.InstanceNotReadyReason), err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		if !ready {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.InstanceNotReadyReason,
+				conditions.GetMessage(deployer, deliveryv1alpha1.InstanceReadyCondition))
+
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+	} else {
+		deployer.Status.Instance = nil
+		conditions.Delete(deployer, deliveryv1alpha1.InstanceReadyCondition)
+	}
+
+	progressing, err := checkHealth(ctx, clnt, deployer, applied)
 	if err != nil {
-		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.CreateOrUpdateFailedReason, err.Error())
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HealthCheckFailedReason), err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to check health: %w", err)
+	}
 
-		return ctrl.Result{}, fmt.Errorf("failed to create or update resource graph definition: %w", err)
+	healthCheckFailed := conditions.GetReason(deployer, deliveryv1alpha1.HealthyCondition) == deliveryv1alpha1.HealthCheckFailedReason
+	if healthCheckFailed {
+		cause := errors.New(conditions.GetMessage(deployer, deliveryv1alpha1.HealthyCondition))
+		if rolledBack, err := r.rollback(ctx, clnt, deployer, digest, version, cause); rolledBack || err != nil {
+			return rollbackResult(deployer, err)
+		}
 	}
 
-	logger.Info("applied resource graph definition", "operation", op, "name", actual.Name)
+	// Keep the revision to be able to roll back to it if a later revision fails
+	if !progressing && !healthCheckFailed &&
+		(deployer.Status.LastHealthyRevision == nil || deployer.Status.LastHealthyRevision.Digest != digest ||
+			deployer.Status.LastHealthyRevision.PostRenderDigest != patchedDigest) {
+		healthy, err := healthyRevision(digest, revisions)
+		if err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+			return ctrl.Result{}, err
+		}
 
-	// TODO: Status propagation of RGD status to deployer
-	//       (see https://github.com/open-component-model/ocm-k8s-toolkit/issues/192)
-	status.MarkReady(r.EventRecorder, deployer, "Applied version %s", resourceAccess.Meta().GetVersion())
+		healthy.PostRenderDigest = patchedDigest
+		deployer.Status.LastHealthyRevision = healthy
+		deployer.Status.FailedRevision = nil
+	}
+
+	if restored {
+		markRolledBack(deployer, version)
+	} else {
+		status.MarkReady(r.EventRecorder, deployer, "Applied version %s", version)
+	}
+
+	if progressing {
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: deployer.GetRequeueAfter()}, nil
+}
+
+// rgdConditions maps the conditions kro sets on a resource graph definition to the conditions of the deployer in the
+// order they are propagated. All of them must be true for the resource graph definition to be deployable.
+var rgdConditions = []struct {
+	rgd      krov1alpha1.ConditionType
+	deployer string
+}{
+	{krov1alpha1.ResourceGraphDefinitionConditionTypeGraphVerified, deliveryv1alpha1.GraphVerifiedCondition},
+	{krov1alpha1.ResourceGraphDefinitionConditionTypeCustomResourceDefinitionSynced, deliveryv1alpha1.CustomResourceDefinitionSyncedCondition},
+	{krov1alpha1.ResourceGraphDefinitionConditionTypeReconcilerReady, deliveryv1alpha1.ReconcilerReadyCondition},
+}
+
+// propagateResourceGraphDefinitionStatus copies the conditions of the resource graph definition into the conditions of
+// the deployer. It returns an error if any of the conditions is missing, not true, or was observed for an older
+// generation of the resource graph definition.
+func propagateResourceGraphDefinitionStatus(deployer *deliveryv1alpha1.Deployer, rgd *krov1alpha1.ResourceGraphDefinition) error {
+	var errs []error
+	for _, mapping := range rgdConditions {
+		conditionStatus, msg := resourceGraphDefinitionCondition(rgd, mapping.rgd)
+
+		switch conditionStatus {
+		case metav1.ConditionTrue:
+			conditions.MarkTrue(deployer, mapping.deployer, meta.SucceededReason, "%s", msg)
+		case metav1.ConditionFalse:
+			conditions.MarkFalse(deployer, mapping.deployer, deliveryv1alpha1.ResourceGraphDefinitionNotReadyReason, "%s", msg)
+			errs = append(errs, fmt.Errorf("resource graph definition %s condition %s is false: %s", rgd.GetName(), mapping.rgd, msg))
+		default:
+			conditions.MarkUnknown(deployer, mapping.deployer, deliveryv1alpha1.ResourceGraphDefinitionNotReadyReason, "%s", msg)
+			errs = append(errs, fmt.Errorf("resource graph definition %s condition %s is unknown: %s", rgd.GetName(), mapping.rgd, msg))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resourceGraphDefinitionCondition returns the status and message of the condition of the resource graph definition.
+// Missing conditions and conditions that were observed for an older generation are unknown, as they do not tell
+// anything about the current spec.
+func resourceGraphDefinitionCondition(
+	rgd *krov1alpha1.ResourceGraphDefinition,
+	conditionType krov1alpha1.ConditionType,
+) (metav1.ConditionStatus, string) {
+	idx := slices.IndexFunc(rgd.Status.Conditions, func(c krov1alpha1.Condition) bool {
+		return c.Type == conditionType
+	})
+	if idx < 0 {
+		return metav1.ConditionUnknown, "condition not reported yet"
+	}
+
+	condition := rgd.Status.Conditions[idx]
+	if condition.ObservedGeneration != rgd.GetGeneration() {
+		return metav1.ConditionUnknown, fmt.Sprintf("condition observed for generation %d, waiting for generation %d",
+			condition.ObservedGeneration, rgd.GetGeneration())
+	}
+
+	// kro reports the cause of a failing condition in the reason and a static description in the message.
+	msg := ptr.Deref(condition.Message, "")
+	if reason := ptr.Deref(condition.Reason, ""); reason != "" {
+		msg = reason
+	}
 
-	return ctrl.Result{}, nil
+	return condition.Status, msg
 }
 
 // getResource returns the resource data as byte-slice and its digest.
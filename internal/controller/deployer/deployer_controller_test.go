@@ -7,17 +7,25 @@ import (
 	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"time"
 
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
 	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
 	"github.com/mandelsoft/vfs/pkg/osfs"
 	"github.com/mandelsoft/vfs/pkg/projectionfs"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
 	. "ocm.software/ocm/api/helper/builder"
 	environment "ocm.software/ocm/api/helper/env"
 	ocmmetav1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
@@ -26,6 +34,7 @@ import (
 	"ocm.software/ocm/api/utils/accessio"
 	"ocm.software/ocm/api/utils/mime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/yaml"
 
 	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
@@ -183,16 +192,2183 @@ spec:
 			}
 			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
 
+			By("checking that the deployer waits for kro to accept the ResourceGraphDefinition")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ResourceGraphDefinitionNotReadyReason)
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("checking that the deployed ResourceGraphDefinition is correct")
+			rgdObjApplied := &krov1alpha1.ResourceGraphDefinition{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), rgdObjApplied)).To(Succeed())
+			Expect(rgdObjApplied.Spec).To(Equal(rgdObj.Spec))
+			Expect(rgdObjApplied.GetOwnerReferences()).To(ContainElement(HaveField("Name", deployerObj.GetName())))
+
+			By("checking that the conditions of the ResourceGraphDefinition are propagated")
+			Expect(conditions.IsTrue(deployerObj, v1alpha1.GraphVerifiedCondition)).To(BeTrue())
+			Expect(conditions.IsTrue(deployerObj, v1alpha1.CustomResourceDefinitionSyncedCondition)).To(BeTrue())
+			Expect(conditions.IsTrue(deployerObj, v1alpha1.ReconcilerReadyCondition)).To(BeTrue())
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, rgdObj)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("waits for the instances of the RGD to be deleted before deleting the deployer", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, rgd)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashRgd := sha256.Sum256(rgd)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashRgd[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					DeletionPolicy: v1alpha1.DeletionPolicyWaitForInstances,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(deployerObj.GetFinalizers()).To(ContainElement(v1alpha1.DeployerFinalizer))
+
+			By("mocking kro generating the CRD of the instances")
+			instanceCRD := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "somekinds.kro.run",
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "kro.run",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Kind:     "SomeKind",
+						ListKind: "SomeKindList",
+						Plural:   "somekinds",
+						Singular: "somekind",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+						Name:    "v1alpha1",
+						Served:  true,
+						Storage: true,
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type:                   "object",
+								XPreserveUnknownFields: ptr.To(true),
+							},
+						},
+					}},
+				},
+			}
+			_, err = envtest.InstallCRDs(testEnv.Config, envtest.CRDInstallOptions{
+				CRDs: []*apiextensionsv1.CustomResourceDefinition{instanceCRD},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(func() error {
+				return envtest.UninstallCRDs(testEnv.Config, envtest.CRDInstallOptions{
+					CRDs: []*apiextensionsv1.CustomResourceDefinition{instanceCRD},
+				})
+			})
+
+			By("creating an instance")
+			instance := &unstructured.Unstructured{}
+			instance.SetAPIVersion("kro.run/v1alpha1")
+			instance.SetKind("SomeKind")
+			instance.SetNamespace(namespace.GetName())
+			instance.SetName("some-instance")
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+			By("deleting the deployer")
+			Expect(k8sClient.Delete(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deletion waits for the instance")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.InstancesExistReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), &krov1alpha1.ResourceGraphDefinition{})).To(Succeed())
+
+			By("deleting the instance")
+			Expect(k8sClient.Delete(ctx, instance)).To(Succeed())
+
+			By("checking that the deployer and the RGD are deleted")
+			Eventually(func(ctx context.Context) bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj))
+			}, "30s").WithContext(ctx).Should(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), &krov1alpha1.ResourceGraphDefinition{}))).To(BeTrue())
+		})
+
+		It("creates the instance of the RGD and propagates its status", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, rgd)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashRgd := sha256.Sum256(rgd)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashRgd[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("mocking kro generating the CRD of the instances")
+			instanceCRD := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "somekinds.kro.run",
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "kro.run",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Kind:     "SomeKind",
+						ListKind: "SomeKindList",
+						Plural:   "somekinds",
+						Singular: "somekind",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+						Name:    "v1alpha1",
+						Served:  true,
+						Storage: true,
+						Schema: &apiextensionsv1.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+								Type:                   "object",
+								XPreserveUnknownFields: ptr.To(true),
+							},
+						},
+					}},
+				},
+			}
+			_, err = envtest.InstallCRDs(testEnv.Config, envtest.CRDInstallOptions{
+				CRDs: []*apiextensionsv1.CustomResourceDefinition{instanceCRD},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(func() error {
+				return envtest.UninstallCRDs(testEnv.Config, envtest.CRDInstallOptions{
+					CRDs: []*apiextensionsv1.CustomResourceDefinition{instanceCRD},
+				})
+			})
+
+			By("creating a deployer with an instance")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Instance: &v1alpha1.InstanceSpec{
+						Name:   "some-instance",
+						Values: &apiextensionsv1.JSON{Raw: []byte(`{"testField":"some-value"}`)},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
+			By("checking that the deployer waits for kro to reconcile the instance")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.InstanceNotReadyReason)
+
+			By("checking that the instance is created with the values of the deployer")
+			instance := &unstructured.Unstructured{}
+			instance.SetAPIVersion("kro.run/v1alpha1")
+			instance.SetKind("SomeKind")
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "some-instance"}, instance)).To(Succeed())
+			Expect(instance.Object["spec"]).To(Equal(map[string]any{"testField": "some-value"}))
+			Expect(instance.GetOwnerReferences()).To(ContainElement(HaveField("Name", deployerObj.GetName())))
+
+			By("mocking kro reconciling the instance")
+			Eventually(func(ctx context.Context) error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(instance), instance); err != nil {
+					return err
+				}
+
+				instance.Object["status"] = map[string]any{
+					"state":   "ACTIVE",
+					"podName": "some-name",
+					"conditions": []any{
+						map[string]any{"type": "InstanceSynced", "status": "True", "reason": "Synced"},
+					},
+				}
+
+				return k8sClient.Update(ctx, instance)
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("checking that the status of the instance is propagated")
+			Eventually(func(ctx context.Context) error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj); err != nil {
+					return err
+				}
+
+				if !conditions.IsReady(deployerObj) {
+					return fmt.Errorf("deployer is not ready: %s", conditions.GetMessage(deployerObj, meta.ReadyCondition))
+				}
+
+				return nil
+			}, "30s").WithContext(ctx).Should(Succeed())
+			Expect(conditions.IsTrue(deployerObj, v1alpha1.InstanceReadyCondition)).To(BeTrue())
+			Expect(deployerObj.Status.Instance).NotTo(BeNil())
+			Expect(deployerObj.Status.Instance.Name).To(Equal("some-instance"))
+			Expect(deployerObj.Status.Instance.Namespace).To(Equal(namespace.GetName()))
+			Expect(deployerObj.Status.Instance.State).To(Equal("ACTIVE"))
+			Expect(deployerObj.Status.Instance.Status).NotTo(BeNil())
+			Expect(deployerObj.Status.Instance.Status.Raw).To(MatchJSON(`{"podName":"some-name"}`))
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+
+			By("checking that the instance and the RGD are deleted")
+			Eventually(func(ctx context.Context) bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+			}, "15s").WithContext(ctx).Should(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), &krov1alpha1.ResourceGraphDefinition{}))).To(BeTrue())
+		})
+
+		It("orphans the deployed objects on deletion if configured", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: orphaned-configmap
+  namespace: %s
+data:
+  key: orphaned
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type:           v1alpha1.DeployerTypeManifest,
+					DeletionPolicy: v1alpha1.DeletionPolicyOrphan,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+
+			By("checking that the config map is kept without owner")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "orphaned-configmap"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "orphaned"))
+			Expect(configMap.GetOwnerReferences()).To(BeEmpty())
+
+			By("deleting the orphaned config map")
+			test.DeleteObject(ctx, k8sClient, configMap)
+		})
+
+		It("deploys multiple resources in order and waits for the ready gates", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			secondResourceName := resourceName + "-second"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second-configmap
+  namespace: %s
+data:
+  key: second
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, rgd)
+						})
+						env.Resource(secondResourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking the resources")
+			hashRgd := sha256.Sum256(rgd)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashRgd[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+			hashManifest := sha256.Sum256(manifest)
+			secondResourceObj := test.MockResource(
+				ctx,
+				secondResourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    secondResourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer that waits for the RGD before deploying the config map")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					Resources: []v1alpha1.DeployerResource{
+						{
+							ResourceRef: v1alpha1.ObjectKey{
+								Name:      resourceObj.GetName(),
+								Namespace: namespace.GetName(),
+							},
+							WaitForReady: true,
+						},
+						{
+							ResourceRef: v1alpha1.ObjectKey{
+								Name:      secondResourceObj.GetName(),
+								Namespace: namespace.GetName(),
+							},
+						},
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer waits for the RGD to become ready")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ObjectsNotReadyReason)
+			Expect(deployerObj.Status.Resources).To(HaveLen(2))
+			Expect(deployerObj.Status.Resources[0].Ready).To(BeFalse())
+			Expect(deployerObj.Status.Resources[1].Ready).To(BeFalse())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), &krov1alpha1.ResourceGraphDefinition{})).To(Succeed())
+			configMapKey := client.ObjectKey{Namespace: namespace.GetName(), Name: "second-configmap"}
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, configMapKey, &corev1.ConfigMap{}))).To(BeTrue())
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(deployerObj.Status.Resources).To(HaveEach(HaveField("Ready", BeTrue())))
+			Expect(deployerObj.Status.Inventory).To(HaveLen(2))
+
+			By("checking that the config map is deployed")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "second"))
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, configMap)
+			test.DeleteObject(ctx, k8sClient, rgdObj)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+
+			By("deleting the second resource")
+			test.DeleteObject(ctx, k8sClient, secondResourceObj)
+		})
+
+		It("reports the health of the applied objects", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unhealthy-deployment
+  namespace: %s
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: unhealthy
+  template:
+    metadata:
+      labels:
+        app: unhealthy
+    spec:
+      containers:
+      - name: app
+        image: some-image:latest
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer with a health check")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+					HealthCheck: &v1alpha1.HealthCheckSpec{
+						Timeout: metav1.Duration{Duration: time.Second},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("checking that the deployment that never becomes available is reported unhealthy after the timeout")
+			Eventually(func(ctx context.Context) string {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+
+				return conditions.GetReason(deployerObj, v1alpha1.HealthyCondition)
+			}, "30s").WithContext(ctx).Should(Equal(v1alpha1.HealthCheckFailedReason))
+			Expect(conditions.IsFalse(deployerObj, v1alpha1.HealthyCondition)).To(BeTrue())
+			Expect(conditions.GetMessage(deployerObj, v1alpha1.HealthyCondition)).To(ContainSubstring("unhealthy-deployment"))
+
+			By("mocking the GC")
+			deployment := &unstructured.Unstructured{}
+			deployment.SetAPIVersion("apps/v1")
+			deployment.SetKind("Deployment")
+			deployment.SetNamespace(namespace.GetName())
+			deployment.SetName("unhealthy-deployment")
+			test.DeleteObject(ctx, k8sClient, deployment)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("does not mark a deployer ready when kro rejects the RGD", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, rgd)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashRgd := sha256.Sum256(rgd)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashRgd[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("mocking kro rejecting the ResourceGraphDefinition")
+			Eventually(func(ctx context.Context) error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), &krov1alpha1.ResourceGraphDefinition{})
+			}, "15s").WithContext(ctx).Should(Succeed())
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionFalse, "graph contains a cycle")
+
+			By("checking that the deployer is not ready")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ResourceGraphDefinitionNotReadyReason)
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(conditions.IsFalse(deployerObj, v1alpha1.GraphVerifiedCondition)).To(BeTrue())
+				g.Expect(conditions.GetMessage(deployerObj, v1alpha1.GraphVerifiedCondition)).To(Equal("graph contains a cycle"))
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, rgdObj)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("reconciles a deployer with a multi-document manifest", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first-configmap
+  namespace: %[1]s
+data:
+  key: first
+---
+---
+{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "second-configmap", "namespace": "%[1]s"}, "data": {"key": "second"}}
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("checking that all objects of the manifest are deployed")
+			for name, value := range map[string]string{"first-configmap": "first", "second-configmap": "second"} {
+				configMap := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: name}, configMap)).To(Succeed())
+				Expect(configMap.Data).To(HaveKeyWithValue("key", value))
+				Expect(configMap.GetOwnerReferences()).To(ContainElement(HaveField("Name", deployerObj.GetName())))
+
+				By("mocking the GC")
+				test.DeleteObject(ctx, k8sClient, configMap)
+			}
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("deploys the objects with the permissions of the impersonated service account", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: impersonated-configmap
+  namespace: %s
+data:
+  key: impersonated
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a service account without permissions")
+			serviceAccount := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deployer",
+					Namespace: namespace.GetName(),
+				},
+			}
+			Expect(k8sClient.Create(ctx, serviceAccount)).To(Succeed())
+
+			By("creating a deployer that impersonates the service account")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type:               v1alpha1.DeployerTypeManifest,
+					ServiceAccountName: serviceAccount.GetName(),
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the denied access is reported")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.AccessDeniedReason)
+
+			By("granting the service account the permissions to deploy config maps")
+			role := &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deployer",
+					Namespace: namespace.GetName(),
+				},
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps"},
+					Verbs:     []string{"get", "create", "patch"},
+				}},
+			}
+			Expect(k8sClient.Create(ctx, role)).To(Succeed())
+			roleBinding := &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deployer",
+					Namespace: namespace.GetName(),
+				},
+				RoleRef: rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "Role",
+					Name:     role.GetName(),
+				},
+				Subjects: []rbacv1.Subject{{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      serviceAccount.GetName(),
+					Namespace: namespace.GetName(),
+				}},
+			}
+			Expect(k8sClient.Create(ctx, roleBinding)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("checking that the config map is managed by the deployer")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "impersonated-configmap"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "impersonated"))
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, configMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("prunes objects that are removed from the manifest", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			firstConfigMap := fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first-configmap
+  namespace: %s
+data:
+  key: first
+`, namespace.GetName())
+			manifest := []byte(fmt.Sprintf(`%s---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second-configmap
+  namespace: %s
+data:
+  key: second
+`, firstConfigMap, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(deployerObj.Status.Inventory).To(HaveLen(2))
+
+			By("updating the mocked resource without the second object")
+			componentVersion = "1.0.1"
+			resourceVersion = "1.0.1"
+			manifestUpdated := []byte(firstConfigMap)
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifestUpdated)
+						})
+					})
+				})
+			})
+
+			spec, err = ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err = spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			resourceObjUpdated := &v1alpha1.Resource{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resourceObj), resourceObjUpdated)).To(Succeed())
+			resourceObjUpdated.Status.Component.Version = componentVersion
+			resourceObjUpdated.Status.Component.RepositorySpec = &apiextensionsv1.JSON{Raw: specData}
+			resourceObjUpdated.Status.Resource.Version = resourceVersion
+			hashManifest = sha256.Sum256(manifestUpdated)
+			resourceObjUpdated.Status.Resource.Digest = fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1")
+			status.MarkReady(recorder, resourceObjUpdated, "updated mock resource")
+			Expect(k8sClient.Status().Update(ctx, resourceObjUpdated)).To(Succeed())
+
+			By("checking that the removed object is pruned")
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(deployerObj.Status.Inventory).To(ConsistOf(v1alpha1.InventoryEntry{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Namespace:  namespace.GetName(),
+					Name:       "first-configmap",
+				}))
+				err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "second-configmap"}, &corev1.ConfigMap{})
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("mocking the GC")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "first-configmap"}, configMap)).To(Succeed())
+			test.DeleteObject(ctx, k8sClient, configMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("rolls back to the last healthy revision if a new revision fails to apply", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: rollback-configmap
+  namespace: %s
+data:
+  key: %s
+`
+			manifest := []byte(fmt.Sprintf(configMap, namespace.GetName(), "healthy"))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type:              v1alpha1.DeployerTypeManifest,
+					RollbackOnFailure: true,
+					Interval:          metav1.Duration{Duration: time.Second},
+					RevertDrift:       true,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the healthy revision is kept")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(deployerObj.Status.LastHealthyRevision).NotTo(BeNil())
+			Expect(deployerObj.Status.LastHealthyRevision.Resources).To(HaveLen(1))
+			Expect(deployerObj.Status.LastHealthyRevision.Resources[0].ComponentVersion).To(Equal(componentVersion))
+
+			By("updating the mocked resource with an object that cannot be applied")
+			componentVersion = "1.0.1"
+			resourceVersion = "1.0.1"
+			manifestUpdated := []byte(fmt.Sprintf(`%s---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Invalid_Name
+  namespace: %s
+`, fmt.Sprintf(configMap, namespace.GetName(), "failed"), namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifestUpdated)
+						})
+					})
+				})
+			})
+
+			spec, err = ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err = spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			resourceObjUpdated := &v1alpha1.Resource{}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resourceObj), resourceObjUpdated)).To(Succeed())
+			resourceObjUpdated.Status.Component.Version = componentVersion
+			resourceObjUpdated.Status.Component.RepositorySpec = &apiextensionsv1.JSON{Raw: specData}
+			resourceObjUpdated.Status.Resource.Version = resourceVersion
+			hashManifest = sha256.Sum256(manifestUpdated)
+			resourceObjUpdated.Status.Resource.Digest = fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1")
+			status.MarkReady(recorder, resourceObjUpdated, "updated mock resource")
+			Expect(k8sClient.Status().Update(ctx, resourceObjUpdated)).To(Succeed())
+
+			By("checking that the failed revision is rolled back")
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(conditions.GetReason(deployerObj, meta.ReadyCondition)).To(Equal(v1alpha1.RolledBackReason))
+				g.Expect(deployerObj.Status.FailedRevision).NotTo(BeNil())
+				g.Expect(deployerObj.Status.FailedRevision.Version).To(Equal(resourceVersion))
+				g.Expect(deployerObj.Status.LastAppliedDigest).To(Equal(deployerObj.Status.LastHealthyRevision.Digest))
+
+				cm := &corev1.ConfigMap{}
+				g.Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "rollback-configmap"}, cm)).To(Succeed())
+				g.Expect(cm.Data).To(HaveKeyWithValue("key", "healthy"))
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("checking that drift of the restored revision is reverted")
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "rollback-configmap"}, cm)).To(Succeed())
+			cm.Data["key"] = "drifted"
+			Expect(k8sClient.Update(ctx, cm)).To(Succeed())
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+				g.Expect(cm.Data).To(HaveKeyWithValue("key", "healthy"))
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(conditions.GetReason(deployerObj, meta.ReadyCondition)).To(Equal(v1alpha1.RolledBackReason))
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("mocking the GC")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+			test.DeleteObject(ctx, k8sClient, cm)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("validates all objects before applying any of them", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: valid-configmap
+  namespace: %[1]s
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: Invalid_Name
+  namespace: %[1]s
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the validation failure is reported with the field path")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ValidationFailedReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(conditions.GetMessage(deployerObj, meta.ReadyCondition)).To(ContainSubstring("metadata.name"))
+
+			By("checking that no object was applied")
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "valid-configmap"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+			Expect(deployerObj.Status.Inventory).To(BeEmpty())
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("reports ownership conflicts and adopts the objects if enabled", func(ctx SpecContext) {
+			By("creating a config map controlled by another deployer")
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "owned-configmap",
+					Namespace: namespace.GetName(),
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: v1alpha1.GroupVersion.String(),
+						Kind:       "Deployer",
+						Name:       "other-deployer",
+						UID:        "00000000-0000-0000-0000-000000000001",
+						Controller: ptr.To(true),
+					}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: owned-configmap
+  namespace: %s
+data:
+  key: value
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the conflict names the other deployer")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.OwnershipConflictReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(conditions.GetMessage(deployerObj, meta.ReadyCondition)).To(ContainSubstring("Deployer other-deployer"))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(configMap.Data).To(BeEmpty())
+
+			By("enabling the adoption")
+			Eventually(func(ctx context.Context) error {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj); err != nil {
+					return err
+				}
+				deployerObj.Spec.AdoptObjects = true
+
+				return k8sClient.Update(ctx, deployerObj)
+			}).WithContext(ctx).Should(Succeed())
+
+			By("checking that the config map is adopted")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "value"))
+			Expect(metav1.GetControllerOf(configMap).UID).To(Equal(deployerObj.GetUID()))
+			Expect(configMap.GetOwnerReferences()).To(HaveLen(1))
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, configMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("deploys the objects to the remote cluster of the kubeconfig", func(ctx SpecContext) {
+			By("creating a kubeconfig secret for the test cluster")
+			kubeConfig := clientcmdapi.NewConfig()
+			kubeConfig.Clusters["remote"] = &clientcmdapi.Cluster{
+				Server:                   testEnv.Config.Host,
+				CertificateAuthorityData: testEnv.Config.CAData,
+			}
+			kubeConfig.AuthInfos["remote"] = &clientcmdapi.AuthInfo{
+				ClientCertificateData: testEnv.Config.CertData,
+				ClientKeyData:         testEnv.Config.KeyData,
+				Token:                 testEnv.Config.BearerToken,
+			}
+			kubeConfig.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "remote"}
+			kubeConfig.CurrentContext = "remote"
+			kubeConfigData, err := clientcmd.Write(*kubeConfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "remote-kubeconfig",
+					Namespace: namespace.GetName(),
+				},
+				Data: map[string][]byte{"value": kubeConfigData},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: remote-configmap
+  namespace: %s
+data:
+  key: value
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+					KubeConfig: &v1alpha1.KubeConfigReference{
+						SecretRef: meta.SecretKeyReference{Name: secret.GetName()},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the object is annotated with the deployer instead of owned by it")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "remote-configmap"}, configMap)).To(Succeed())
+			Expect(configMap.GetOwnerReferences()).To(BeEmpty())
+			Expect(configMap.GetAnnotations()).To(HaveKeyWithValue("delivery.ocm.software/deployer-uid", string(deployerObj.GetUID())))
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+
+			By("checking that the object is deleted from the remote cluster")
+			Eventually(func(ctx context.Context) bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})
+
+				return errors.IsNotFound(err)
+			}, "15s").WithContext(ctx).Should(BeTrue())
+		})
+
+		It("waits for its dependencies and reports dependency cycles", func(ctx SpecContext) {
+			By("mocking a resource")
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+				},
+			)
+
+			By("creating a deployer that depends on a missing deployer")
+			newDeployer := func(name string, dependsOn ...string) *v1alpha1.Deployer {
+				deployerObj := &v1alpha1.Deployer{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: v1alpha1.DeployerSpec{
+						ResourceRef: v1alpha1.ObjectKey{
+							Name:      resourceObj.GetName(),
+							Namespace: namespace.GetName(),
+						},
+						Type: v1alpha1.DeployerTypeManifest,
+					},
+				}
+				for _, dependency := range dependsOn {
+					deployerObj.Spec.DependsOn = append(deployerObj.Spec.DependsOn, corev1.LocalObjectReference{Name: dependency})
+				}
+
+				return deployerObj
+			}
+			first := newDeployer(deployerObjName+"-first", deployerObjName+"-second")
+			Expect(k8sClient.Create(ctx, first)).To(Succeed())
+
+			test.WaitForNotReadyObject(ctx, k8sClient, first, v1alpha1.DependencyNotReadyReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(first), first)).To(Succeed())
+			Expect(conditions.GetMessage(first, meta.ReadyCondition)).To(ContainSubstring(deployerObjName + "-second (not found)"))
+
+			By("creating the dependency that depends on the first deployer")
+			second := newDeployer(deployerObjName+"-second", deployerObjName+"-first")
+			Expect(k8sClient.Create(ctx, second)).To(Succeed())
+
+			By("checking that both deployers report the cycle")
+			test.WaitForNotReadyObject(ctx, k8sClient, first, v1alpha1.DependencyCycleReason)
+			test.WaitForNotReadyObject(ctx, k8sClient, second, v1alpha1.DependencyCycleReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(second), second)).To(Succeed())
+			Expect(conditions.GetMessage(second, meta.ReadyCondition)).To(ContainSubstring(
+				fmt.Sprintf("%[1]s-second -> %[1]s-first -> %[1]s-second", deployerObjName)))
+
+			By("deleting the deployers")
+			test.DeleteObject(ctx, k8sClient, first)
+			test.DeleteObject(ctx, k8sClient, second)
+		})
+
+		It("reports field manager conflicts and takes over the fields when forced", func(ctx SpecContext) {
+			By("creating a config map managed by another field manager")
+			configMap := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "conflicting-configmap",
+					Namespace: namespace.GetName(),
+				},
+				Data: map[string]string{"key": "other"},
+			}
+			Expect(k8sClient.Patch(ctx, configMap, client.Apply, client.FieldOwner("other-manager"))).To(Succeed())
+
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: conflicting-configmap
+  namespace: %s
+data:
+  key: deployer
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer reports the conflict")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ApplyConflictReason)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "other"))
+
+			By("forcing the conflicts")
+			deployerPatch := client.MergeFrom(deployerObj.DeepCopy())
+			deployerObj.Spec.Force = true
+			Expect(k8sClient.Patch(ctx, deployerObj, deployerPatch)).To(Succeed())
+
+			By("checking that the deployer took over the fields")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "deployer"))
+			Expect(configMap.GetManagedFields()).To(ContainElement(HaveField("Manager", fieldManager)))
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, configMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("detects drift of applied objects and reverts it if enabled", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: drifting-configmap
+  namespace: %s
+data:
+  key: deployer
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type:     v1alpha1.DeployerTypeManifest,
+					Interval: metav1.Duration{Duration: time.Second},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
 			By("checking that the deployer has been reconciled successfully")
 			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
 
-			By("checking that the deployed ResourceGraphDefinition is correct")
-			rgdObjApplied := &krov1alpha1.ResourceGraphDefinition{}
-			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rgdObj), rgdObjApplied)).To(Succeed())
-			Expect(rgdObjApplied.Spec).To(Equal(rgdObj.Spec))
+			By("changing the applied object manually")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "drifting-configmap"}, configMap)).To(Succeed())
+			configMap.Data["key"] = "manual"
+			Expect(k8sClient.Update(ctx, configMap)).To(Succeed())
+
+			By("checking that the drift is reported but not reverted")
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(conditions.IsTrue(deployerObj, v1alpha1.DriftedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(deployerObj, v1alpha1.DriftedCondition)).To(Equal(v1alpha1.DriftDetectedReason))
+				g.Expect(conditions.GetMessage(deployerObj, v1alpha1.DriftedCondition)).To(ContainSubstring(".data.key"))
+			}, "15s").WithContext(ctx).Should(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "manual"))
+
+			By("enabling the reversion of drift")
+			deployerPatch := client.MergeFrom(deployerObj.DeepCopy())
+			deployerObj.Spec.RevertDrift = true
+			Expect(k8sClient.Patch(ctx, deployerObj, deployerPatch)).To(Succeed())
+
+			By("checking that the drift is reverted")
+			Eventually(func(g Gomega, ctx context.Context) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+				g.Expect(configMap.Data).To(HaveKeyWithValue("key", "deployer"))
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+				g.Expect(conditions.IsTrue(deployerObj, v1alpha1.DriftedCondition)).To(BeFalse())
+			}, "15s").WithContext(ctx).Should(Succeed())
 
 			By("mocking the GC")
-			test.DeleteObject(ctx, k8sClient, rgdObj)
+			test.DeleteObject(ctx, k8sClient, configMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("reports the changes of a deployer in dry-run mode without applying them", func(ctx SpecContext) {
+			By("creating a config map as applied by a previous revision")
+			existingConfigMap := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "existing-configmap",
+					Namespace: namespace.GetName(),
+				},
+				Data: map[string]string{"key": "old"},
+			}
+			Expect(k8sClient.Patch(ctx, existingConfigMap, client.Apply, client.FieldOwner(fieldManager))).To(Succeed())
+
+			By("creating a CTF")
+			resourceType := artifacttypes.PLAIN_TEXT
+			resourceVersion := "1.0.0"
+			manifest := []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: existing-configmap
+  namespace: %[1]s
+data:
+  key: new
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: new-configmap
+  namespace: %[1]s
+data:
+  key: new
+`, namespace.GetName()))
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, manifest)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashManifest := sha256.Sum256(manifest)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashManifest[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer in dry-run mode")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeManifest,
+					Mode: v1alpha1.DeployerModeDryRun,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer reports the changes")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(deployerObj.Status.DryRun).NotTo(BeNil())
+			Expect(deployerObj.Status.DryRun.Created).To(ConsistOf(HaveField("Name", "new-configmap")))
+			Expect(deployerObj.Status.DryRun.Updated).To(ConsistOf(HaveField("Name", "existing-configmap")))
+			Expect(deployerObj.Status.DryRun.Unchanged).To(BeEmpty())
+			Expect(deployerObj.Status.DryRun.Patch).To(ContainSubstring(`"data":{"key":"new"}`))
+			Expect(deployerObj.Status.Inventory).To(BeEmpty())
+
+			By("checking that nothing was applied")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(existingConfigMap), existingConfigMap)).To(Succeed())
+			Expect(existingConfigMap.Data).To(HaveKeyWithValue("key", "old"))
+			err = k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "new-configmap"}, &corev1.ConfigMap{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			By("cleaning up the config map")
+			test.DeleteObject(ctx, k8sClient, existingConfigMap)
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+		})
+
+		It("installs a helm chart and uninstalls it on deletion", func(ctx SpecContext) {
+			By("creating the values of the release")
+			valuesConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "helm-values",
+					Namespace: namespace.GetName(),
+				},
+				Data: map[string]string{"values.yaml": "key: from-values\n"},
+			}
+			Expect(k8sClient.Create(ctx, valuesConfigMap)).To(Succeed())
+
+			By("creating a CTF")
+			resourceType := artifacttypes.HELM_CHART
+			resourceVersion := "1.0.0"
+			chart := helmChart("simple", map[string]string{
+				"values.yaml": "key: default\n",
+				"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: helm-configmap
+data:
+  key: {{ .Values.key }}
+`,
+			})
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TGZ, chart)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashChart := sha256.Sum256(chart)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashChart[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeHelm,
+					Helm: &v1alpha1.HelmSpec{
+						Namespace: namespace.GetName(),
+						ValuesFrom: []v1alpha1.ValuesReference{{
+							Kind:      "ConfigMap",
+							Name:      valuesConfigMap.GetName(),
+							Namespace: namespace.GetName(),
+						}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployerObj), deployerObj)).To(Succeed())
+			Expect(deployerObj.GetFinalizers()).To(ContainElement(v1alpha1.DeployerFinalizer))
+			Expect(deployerObj.Status.Helm).NotTo(BeNil())
+			Expect(deployerObj.Status.Helm.Name).To(Equal(deployerObjName))
+			Expect(deployerObj.Status.Helm.Namespace).To(Equal(namespace.GetName()))
+			Expect(deployerObj.Status.Helm.Revision).To(Equal(1))
+			Expect(deployerObj.Status.Helm.Status).To(Equal("deployed"))
+			Expect(deployerObj.Status.Helm.ChartName).To(Equal("simple"))
+			Expect(deployerObj.Status.Helm.ChartVersion).To(Equal("1.0.0"))
+
+			By("checking that the chart is installed with the referenced values")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "helm-configmap"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "from-values"))
+
+			By("deleting the deployer")
+			test.DeleteObject(ctx, k8sClient, deployerObj)
+
+			By("checking that the release is uninstalled")
+			Eventually(func(g Gomega, ctx context.Context) {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}, "15s").WithContext(ctx).Should(Succeed())
+
+			By("cleaning up the values")
+			test.DeleteObject(ctx, k8sClient, valuesConfigMap)
+		})
+
+		It("builds and deploys a kustomization with the patches of the deployer", func(ctx SpecContext) {
+			By("creating a CTF")
+			resourceType := artifacttypes.DIRECTORY_TREE
+			resourceVersion := "1.0.0"
+			archive := tarGz(map[string][]byte{
+				"kustomization.yaml": []byte(fmt.Sprintf(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: %s
+resources:
+- configmap.yaml
+`, namespace.GetName())),
+				"configmap.yaml": []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kustomize-configmap
+data:
+  key: kustomization
+`),
+			})
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, resourceVersion, resourceType, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TGZ, archive)
+						})
+					})
+				})
+			})
+
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a resource")
+			hashArchive := sha256.Sum256(archive)
+			resourceObj = test.MockResource(
+				ctx,
+				resourceName,
+				namespace.GetName(),
+				&test.MockResourceOptions{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentName,
+					},
+					Clnt:     k8sClient,
+					Recorder: recorder,
+					ComponentInfo: &v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					ResourceInfo: &v1alpha1.ResourceInfo{
+						Name:    resourceName,
+						Type:    resourceType,
+						Version: resourceVersion,
+						Access:  apiextensionsv1.JSON{Raw: []byte("{}")},
+						// TODO: Consider calculating the digest the ocm-way
+						Digest: fmt.Sprintf("SHA-256:%s[%s]", hex.EncodeToString(hashArchive[:]), "genericBlobDigest/v1"),
+					},
+				},
+			)
+
+			By("creating a deployer")
+			deployerObj := &v1alpha1.Deployer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: deployerObjName,
+				},
+				Spec: v1alpha1.DeployerSpec{
+					ResourceRef: v1alpha1.ObjectKey{
+						Name:      resourceObj.GetName(),
+						Namespace: namespace.GetName(),
+					},
+					Type: v1alpha1.DeployerTypeKustomize,
+					Kustomize: &v1alpha1.KustomizeSpec{
+						Patches: []v1alpha1.KustomizePatch{{
+							Patch: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kustomize-configmap
+data:
+  key: patched
+`,
+						}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
+
+			By("checking that the deployer has been reconciled successfully")
+			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
+
+			By("checking that the patched kustomization is deployed")
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace.GetName(), Name: "kustomize-configmap"}, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveKeyWithValue("key", "patched"))
+
+			By("mocking the GC")
+			test.DeleteObject(ctx, k8sClient, configMap)
 
 			By("deleting the deployer")
 			test.DeleteObject(ctx, k8sClient, deployerObj)
@@ -449,6 +2625,12 @@ spec:
 			}
 			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
 
+			By("checking that the deployer waits for kro to accept the ResourceGraphDefinition")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ResourceGraphDefinitionNotReadyReason)
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
 			By("checking that the deployer has been reconciled successfully")
 			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
 
@@ -569,6 +2751,12 @@ spec:
 			}
 			Expect(k8sClient.Create(ctx, deployerObj)).To(Succeed())
 
+			By("checking that the deployer waits for kro to accept the ResourceGraphDefinition")
+			test.WaitForNotReadyObject(ctx, k8sClient, deployerObj, v1alpha1.ResourceGraphDefinitionNotReadyReason)
+
+			By("mocking kro accepting the ResourceGraphDefinition")
+			mockResourceGraphDefinitionStatus(ctx, rgdObj, metav1.ConditionTrue, "")
+
 			By("checking that the deployer has been reconciled successfully")
 			test.WaitForReadyObject(ctx, k8sClient, deployerObj, map[string]any{})
 
@@ -622,3 +2810,31 @@ spec:
 		})
 	})
 })
+
+// mockResourceGraphDefinitionStatus mimics kro by setting the conditions of the applied resource graph definition.
+func mockResourceGraphDefinitionStatus(
+	ctx context.Context,
+	rgd *krov1alpha1.ResourceGraphDefinition,
+	conditionStatus metav1.ConditionStatus,
+	reason string,
+) {
+	GinkgoHelper()
+
+	Eventually(func(ctx context.Context) error {
+		applied := &krov1alpha1.ResourceGraphDefinition{}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(rgd), applied); err != nil {
+			return err
+		}
+
+		applied.Status.Conditions = []krov1alpha1.Condition{
+			krov1alpha1.NewCondition(krov1alpha1.ResourceGraphDefinitionConditionTypeGraphVerified, conditionStatus, reason, "Directed Acyclic Graph is synced"),
+			krov1alpha1.NewCondition(krov1alpha1.ResourceGraphDefinitionConditionTypeCustomResourceDefinitionSynced, conditionStatus, reason, "Custom Resource Definition is synced"),
+			krov1alpha1.NewCondition(krov1alpha1.ResourceGraphDefinitionConditionTypeReconcilerReady, conditionStatus, reason, "micro controller is ready"),
+		}
+		for i := range applied.Status.Conditions {
+			applied.Status.Conditions[i].ObservedGeneration = applied.GetGeneration()
+		}
+
+		return k8sClient.Status().Update(ctx, applied)
+	}, "15s").WithContext(ctx).Should(Succeed())
+}
@@ -0,0 +1,88 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// detectDrift returns the live object and the fields in which it differs from the desired object. The desired state
+// is determined by a server-side dry-run apply, so that fields defaulted by the API server are not reported as drift.
+// If the object does not exist, no live object is returned.
+func (r *Reconciler) detectDrift(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	desired *unstructured.Unstructured,
+) (*unstructured.Unstructured, []string, error) {
+	obj, err := prepareObject(clnt, deployer, desired)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("failed to get %s: %w", objectRef(obj), err)
+	}
+
+	if err := clnt.Patch(ctx, obj, client.Apply, client.FieldOwner(fieldManager), client.ForceOwnership, client.DryRunAll); err != nil {
+		return nil, nil, fmt.Errorf("failed to dry-run apply %s: %w", objectRef(obj), err)
+	}
+
+	return live, diffFields("", withoutServerFields(live).Object, withoutServerFields(obj).Object), nil
+}
+
+// withoutServerFields returns a copy of the object without the fields that change with every write.
+func withoutServerFields(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetManagedFields(nil)
+
+	return obj
+}
+
+// diffFields returns the paths of the fields that differ between the actual and the expected value. Maps are compared
+// field by field, all other values as a whole.
+func diffFields(path string, actual, expected any) []string {
+	actualMap, actualIsMap := actual.(map[string]any)
+	expectedMap, expectedIsMap := expected.(map[string]any)
+	if !actualIsMap || !expectedIsMap {
+		if equality.Semantic.DeepEqual(actual, expected) {
+			return nil
+		}
+
+		return []string{path}
+	}
+
+	keys := make([]string, 0, len(actualMap)+len(expectedMap))
+	for key := range actualMap {
+		keys = append(keys, key)
+	}
+	for key := range expectedMap {
+		if _, ok := actualMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var diff []string
+	for _, key := range keys {
+		diff = append(diff, diffFields(path+"."+key, actualMap[key], expectedMap[key])...)
+	}
+
+	return diff
+}
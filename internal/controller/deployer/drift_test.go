@@ -0,0 +1,43 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diffFields", func() {
+	It("returns the sorted paths of changed, added, and removed fields", func() {
+		actual := map[string]any{
+			"data": map[string]any{
+				"changed": "manual",
+				"added":   "manual",
+				"same":    "value",
+			},
+			"spec": map[string]any{
+				"list": []any{"a", "b"},
+			},
+		}
+		expected := map[string]any{
+			"data": map[string]any{
+				"changed": "desired",
+				"removed": "desired",
+				"same":    "value",
+			},
+			"spec": map[string]any{
+				"list": []any{"a"},
+			},
+		}
+
+		Expect(diffFields("", actual, expected)).To(Equal([]string{
+			".data.added",
+			".data.changed",
+			".data.removed",
+			".spec.list",
+		}))
+	})
+
+	It("returns nothing for equal objects", func() {
+		obj := map[string]any{"data": map[string]any{"key": int64(1)}}
+		Expect(diffFields("", obj, obj)).To(BeEmpty())
+	})
+})
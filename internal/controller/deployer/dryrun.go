@@ -0,0 +1,115 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// secretGroupKind is the group and kind of secrets, whose patches are not reported in the dry-run summary. Secrets
+// may be generated from encrypted files, e.g. by a secret generator of a kustomization.
+var secretGroupKind = corev1.SchemeGroupVersion.WithKind("Secret").GroupKind()
+
+// maxDryRunPatchSize is the maximum size of the patch in the dry-run summary to keep the status of the deployer small.
+const maxDryRunPatchSize = 4096
+
+// dryRun applies the objects with a server-side dry-run and summarizes the changes compared to the live objects. The
+// patches of secrets and of the decrypted objects (see unmarkDecrypted) are redacted.
+func (r *Reconciler) dryRun(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	objs []*unstructured.Unstructured,
+	decrypted map[string]bool,
+) (*deliveryv1alpha1.DryRunSummary, error) {
+	summary := &deliveryv1alpha1.DryRunSummary{}
+	inventory := make([]deliveryv1alpha1.InventoryEntry, 0, len(objs))
+
+	var patches strings.Builder
+	for _, obj := range objs {
+		result, err := r.applyObject(ctx, clnt, deployer, obj, deployer.Spec.Force, client.DryRunAll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dry-run apply %s: %w", objectRef(obj), err)
+		}
+
+		entry := inventoryEntry(result)
+		inventory = append(inventory, entry)
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(result.GroupVersionKind())
+		if err := clnt.Get(ctx, client.ObjectKeyFromObject(result), live); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get %s: %w", objectRef(result), err)
+			}
+
+			summary.Created = append(summary.Created, entry)
+
+			continue
+		}
+
+		patch, err := mergePatch(live, result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create patch for %s: %w", objectRef(result), err)
+		}
+
+		if string(patch) == "{}" {
+			summary.Unchanged = append(summary.Unchanged, entry)
+
+			continue
+		}
+
+		summary.Updated = append(summary.Updated, entry)
+
+		// The patch of a secret or a decrypted object would reveal its decrypted data
+		if result.GroupVersionKind().GroupKind() == secretGroupKind || decrypted[objectRef(obj)] {
+			fmt.Fprintf(&patches, "%s: (redacted)\n", objectRef(result))
+
+			continue
+		}
+
+		fmt.Fprintf(&patches, "%s: %s\n", objectRef(result), patch)
+	}
+
+	if ptr.Deref(deployer.Spec.Prune, true) {
+		summary.Pruned = staleEntries(deployer.Status.Inventory, inventory)
+	}
+
+	summary.Patch = truncate(patches.String(), maxDryRunPatchSize)
+
+	return summary, nil
+}
+
+// mergePatch returns the JSON merge patch that transforms the live object into the desired object.
+func mergePatch(live, desired *unstructured.Unstructured) ([]byte, error) {
+	original, err := json.Marshal(withoutServerFields(live).Object)
+	if err != nil {
+		return nil, err
+	}
+
+	modified, err := json.Marshal(withoutServerFields(desired).Object)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonpatch.CreateMergePatch(original, modified)
+}
+
+// truncate cuts the string to the maximum size and marks it as truncated.
+func truncate(s string, maxSize int) string {
+	if len(s) <= maxSize {
+		return s
+	}
+
+	return strings.ToValidUTF8(s[:maxSize], "") + "\n... (truncated)"
+}
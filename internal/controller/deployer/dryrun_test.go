@@ -0,0 +1,38 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("mergePatch", func() {
+	It("ignores fields that change with every write", func() {
+		live := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":            "configmap",
+				"resourceVersion": "1",
+			},
+			"data": map[string]any{"key": "old", "other": "value"},
+		}}
+		desired := live.DeepCopy()
+		desired.SetResourceVersion("2")
+		desired.Object["data"] = map[string]any{"key": "new", "other": "value"}
+
+		patch, err := mergePatch(live, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(patch)).To(Equal(`{"data":{"key":"new"}}`))
+	})
+})
+
+var _ = Describe("truncate", func() {
+	It("keeps short strings", func() {
+		Expect(truncate("patch", 10)).To(Equal("patch"))
+	})
+
+	It("truncates long strings without splitting runes", func() {
+		Expect(truncate("aä", 2)).To(Equal("a\n... (truncated)"))
+	})
+})
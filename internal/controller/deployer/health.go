@@ -0,0 +1,116 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kstatus "github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// checkHealth assesses the health of the applied objects and of the instance created by the deployer and reports the
+// result in the Healthy condition of the deployer. Instances of the resource graph definition that were created by
+// others do not affect the health of the deployer. It returns true if objects are still progressing and the health
+// must be checked again.
+func checkHealth(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	objs []*unstructured.Unstructured,
+) (bool, error) {
+	if deployer.Spec.HealthCheck == nil {
+		conditions.Delete(deployer, deliveryv1alpha1.HealthyCondition)
+
+		return false, nil
+	}
+
+	var instances []*unstructured.Unstructured
+	if entry, ok := instanceEntry(deployer); ok {
+		instance := &unstructured.Unstructured{}
+		instance.SetAPIVersion(entry.APIVersion)
+		instance.SetKind(entry.Kind)
+		instance.SetNamespace(entry.Namespace)
+		instance.SetName(entry.Name)
+		instances = append(instances, instance)
+	}
+
+	var failed, progressing []string
+	for _, obj := range slices.Concat(objs, instances) {
+		result, err := objectHealth(ctx, clnt, obj)
+		if err != nil {
+			return false, err
+		}
+
+		switch result.Status {
+		case kstatus.CurrentStatus:
+		case kstatus.FailedStatus:
+			failed = append(failed, fmt.Sprintf("%s (%s)", objectRef(obj), result.Message))
+		default:
+			progressing = append(progressing, objectRef(obj))
+		}
+	}
+
+	started := time.Now()
+	if deployer.Status.LastAppliedTime != nil {
+		started = deployer.Status.LastAppliedTime.Time
+	}
+
+	switch {
+	case len(failed) > 0:
+		conditions.MarkFalse(deployer, deliveryv1alpha1.HealthyCondition, deliveryv1alpha1.HealthCheckFailedReason,
+			"Failed objects: %s", strings.Join(failed, "; "))
+	case len(progressing) == 0:
+		conditions.MarkTrue(deployer, deliveryv1alpha1.HealthyCondition, meta.SucceededReason,
+			"All %d objects are healthy", len(objs)+len(instances))
+	case time.Since(started) > deployer.Spec.HealthCheck.Timeout.Duration:
+		conditions.MarkFalse(deployer, deliveryv1alpha1.HealthyCondition, deliveryv1alpha1.HealthCheckFailedReason,
+			"Timeout waiting for %s to become healthy", strings.Join(progressing, ", "))
+	default:
+		conditions.MarkUnknown(deployer, deliveryv1alpha1.HealthyCondition, meta.ProgressingReason,
+			"Waiting for %s to become healthy", strings.Join(progressing, ", "))
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// objectHealth returns the health of the live state of the object.
+func objectHealth(ctx context.Context, clnt client.Client, obj *unstructured.Unstructured) (*kstatus.Result, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &kstatus.Result{Status: kstatus.NotFoundStatus, Message: "Resource not found"}, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s: %w", objectRef(obj), err)
+	}
+
+	return computeHealth(live)
+}
+
+// computeHealth computes the health of the object with kstatus. Additionally, objects whose Ready condition is not true
+// and resource graph definitions that kro did not accept yet are in progress.
+func computeHealth(obj *unstructured.Unstructured) (*kstatus.Result, error) {
+	result, err := kstatus.Compute(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute status of %s: %w", objectRef(obj), err)
+	}
+
+	if result.Status == kstatus.CurrentStatus && !isObjectReady(obj) {
+		return &kstatus.Result{Status: kstatus.InProgressStatus, Message: "Resource is not ready"}, nil
+	}
+
+	return result, nil
+}
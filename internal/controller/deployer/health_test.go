@@ -0,0 +1,78 @@
+package deployer
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	kstatus "github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("checkHealth", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	deployer := func() *deliveryv1alpha1.Deployer {
+		return &deliveryv1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Name: "health"},
+			Spec: deliveryv1alpha1.DeployerSpec{
+				HealthCheck: &deliveryv1alpha1.HealthCheckSpec{Timeout: metav1.Duration{Duration: time.Minute}},
+			},
+		}
+	}
+
+	It("assesses the instance created by the deployer", func() {
+		d := deployer()
+		d.Status.Instance = &deliveryv1alpha1.InstanceStatus{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "default",
+			Name:       "health-instance-missing",
+		}
+
+		progressing, err := checkHealth(ctx, k8sClient, d, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progressing).To(BeTrue())
+		Expect(conditions.GetMessage(d, deliveryv1alpha1.HealthyCondition)).To(ContainSubstring("health-instance-missing"))
+	})
+})
+
+var _ = Describe("computeHealth", func() {
+	It("reports objects without status as current", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": "test", "namespace": "default"},
+		}}
+
+		result, err := computeHealth(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(kstatus.CurrentStatus))
+	})
+
+	It("reports objects whose Ready condition is not true as in progress", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Example",
+			"metadata":   map[string]any{"name": "test", "namespace": "default", "generation": int64(1)},
+			"status": map[string]any{
+				"observedGeneration": int64(1),
+				"conditions":         []any{map[string]any{"type": "Ready", "status": "False"}},
+			},
+		}}
+
+		result, err := computeHealth(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status).To(Equal(kstatus.InProgressStatus))
+	})
+})
@@ -0,0 +1,368 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
+)
+
+// helmStorageDriver stores the Helm releases as secrets in the namespace of the release, like the Helm CLI does.
+const helmStorageDriver = "secret"
+
+// reconcileHelmRelease installs or upgrades the Helm chart contained in the resource data as release. The release is
+// only upgraded if the chart, the values, or the release itself changed since the last reconciliation.
+func (r *Reconciler) reconcileHelmRelease(
+	ctx context.Context,
+	deployer *deliveryv1alpha1.Deployer,
+	revision *resourceRevision,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if deployer.Spec.Mode == deliveryv1alpha1.DeployerModeDryRun {
+		status.MarkAsStalled(r.EventRecorder, deployer, deliveryv1alpha1.DryRunFailedReason,
+			"dry-run mode is not supported for deployers of type Helm")
+
+		return ctrl.Result{}, nil
+	}
+
+	archive, err := chartArchive(revision.data)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get helm chart: %w", err)
+	}
+
+	chart, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to load helm chart: %w", err)
+	}
+
+	values, err := r.getValues(ctx, deployer, ptr.Deref(deployer.Spec.Helm, deliveryv1alpha1.HelmSpec{}).ValuesFrom)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetValuesFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get values: %w", err)
+	}
+
+	valuesDigest, err := digestValues(values)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	name, namespace, err := helmReleaseKey(deployer)
+	if err != nil {
+		status.MarkAsStalled(r.EventRecorder, deployer, deliveryv1alpha1.HelmReleaseFailedReason, err.Error())
+
+		return ctrl.Result{}, nil
+	}
+
+	// Helm cannot rename or move a release, so the installed release is uninstalled before the release is installed
+	// with the new name or into the new namespace.
+	if installed := deployer.Status.Helm; installed != nil && (installed.Name != name || installed.Namespace != namespace) {
+		logger.Info("uninstalling helm release as its name or namespace changed",
+			"release", installed.Name, "namespace", installed.Namespace)
+		if err := r.uninstallHelmRelease(ctx, deployer); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HelmReleaseFailedReason), err.Error())
+
+			return ctrl.Result{}, err
+		}
+
+		deployer.Status.Helm = nil
+	}
+
+	restConfig, err := r.restConfig(ctx, deployer)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.HelmReleaseFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	cfg, err := r.helmConfiguration(ctx, restConfig, namespace)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.HelmReleaseFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	last, err := cfg.Releases.Last(name)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HelmReleaseFailedReason), err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get helm release %s/%s: %w", namespace, name, err)
+	}
+
+	// A release stays pending if the controller was interrupted while installing or upgrading it. Helm refuses to
+	// upgrade pending releases, so the release is marked as failed to be installed or upgraded again.
+	if last != nil && last.Info.Status.IsPending() {
+		logger.Info("unlocking pending helm release", "release", name, "namespace", namespace, "status", last.Info.Status.String())
+		last.SetStatus(release.StatusFailed, fmt.Sprintf("Release unlocked from stale %s state", last.Info.Status))
+		if err := cfg.Releases.Update(last); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HelmReleaseFailedReason), err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to unlock pending helm release %s/%s: %w", namespace, name, err)
+		}
+	}
+
+	// Helm cannot upgrade a release that was never deployed successfully, so it is installed again.
+	if last != nil && last.Version == 1 && last.Info.Status == release.StatusFailed {
+		logger.Info("uninstalling failed helm release", "release", name, "namespace", namespace)
+		if _, err := action.NewUninstall(cfg).Run(name); err != nil {
+			status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HelmReleaseFailedReason), err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to uninstall failed helm release %s/%s: %w", namespace, name, err)
+		}
+
+		last = nil
+	}
+
+	var rel *release.Release
+	var upToDate bool
+	switch {
+	case last == nil:
+		install := action.NewInstall(cfg)
+		install.ReleaseName = name
+		install.Namespace = namespace
+		install.CreateNamespace = ptr.Deref(deployer.Spec.Helm, deliveryv1alpha1.HelmSpec{}).CreateNamespace
+		rel, err = install.RunWithContext(ctx, chart, values)
+	case isHelmReleaseUpToDate(deployer, last, revision.digest, valuesDigest):
+		rel = last
+		upToDate = true
+	default:
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		rel, err = upgrade.RunWithContext(ctx, name, chart, values)
+	}
+
+	if rel != nil {
+		deployer.Status.Helm = &deliveryv1alpha1.HelmReleaseStatus{
+			Name:         rel.Name,
+			Namespace:    rel.Namespace,
+			Revision:     rel.Version,
+			Status:       rel.Info.Status.String(),
+			ChartName:    chart.Metadata.Name,
+			ChartVersion: chart.Metadata.Version,
+			ValuesDigest: valuesDigest,
+		}
+	}
+
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HelmReleaseFailedReason), err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to install or upgrade helm release %s/%s: %w", namespace, name, err)
+	}
+
+	logger.Info("applied helm release", "release", rel.Name, "namespace", rel.Namespace, "revision", rel.Version)
+
+	if !upToDate || deployer.Status.ObservedGeneration != deployer.GetGeneration() {
+		deployer.Status.LastAppliedTime = &metav1.Time{Time: time.Now()}
+	}
+
+	clnt, err := r.deployClient(ctx, deployer)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.HelmReleaseFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	objs, err := releaseObjects(clnt, rel)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.MarshalFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	progressing, err := checkHealth(ctx, clnt, deployer, objs)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.HealthCheckFailedReason), err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to check health: %w", err)
+	}
+
+	resourceState := resourceStatus(revision)
+	resourceState.Ready = true
+	resourceState.Message = fmt.Sprintf("Applied version %s", revision.version)
+	deployer.Status.Resources = []deliveryv1alpha1.DeployedResourceStatus{resourceState}
+	deployer.Status.LastAppliedDigest = revision.digest
+	deployer.Status.DryRun = nil
+	status.MarkReady(r.EventRecorder, deployer, "Applied version %s as helm release %s/%s revision %d",
+		revision.version, rel.Namespace, rel.Name, rel.Version)
+
+	if progressing {
+		return ctrl.Result{RequeueAfter: pollInterval}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: deployer.GetRequeueAfter()}, nil
+}
+
+// releaseObjects returns the objects of the manifest of the release. Namespaced objects without namespace are
+// deployed into the namespace of the release.
+func releaseObjects(clnt client.Client, rel *release.Release) ([]*unstructured.Unstructured, error) {
+	if strings.TrimSpace(rel.Manifest) == "" {
+		return nil, nil
+	}
+
+	objs, err := decodeManifest([]byte(rel.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of helm release %s/%s: %w", rel.Namespace, rel.Name, err)
+	}
+
+	for _, obj := range objs {
+		if obj.GetNamespace() != "" {
+			continue
+		}
+
+		namespaced, err := clnt.IsObjectNamespaced(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine scope of %s: %w", objectRef(obj), err)
+		}
+		if namespaced {
+			obj.SetNamespace(rel.Namespace)
+		}
+	}
+
+	return objs, nil
+}
+
+// isHelmReleaseUpToDate returns true if the release was deployed by the last reconciliation of the deployer with the
+// same chart and values.
+func isHelmReleaseUpToDate(deployer *deliveryv1alpha1.Deployer, last *release.Release, digest, valuesDigest string) bool {
+	helmStatus := deployer.Status.Helm
+
+	return last.Info.Status == release.StatusDeployed &&
+		helmStatus != nil &&
+		helmStatus.Revision == last.Version &&
+		helmStatus.ValuesDigest == valuesDigest &&
+		deployer.Status.LastAppliedDigest == digest
+}
+
+// uninstallHelmRelease uninstalls the Helm release of the deployer. The release that was installed last is
+// uninstalled, even if the spec names another release. A release that does not exist is ignored.
+func (r *Reconciler) uninstallHelmRelease(ctx context.Context, deployer *deliveryv1alpha1.Deployer) error {
+	var name, namespace string
+	if installed := deployer.Status.Helm; installed != nil {
+		name, namespace = installed.Name, installed.Namespace
+	} else {
+		var err error
+		if name, namespace, err = helmReleaseKey(deployer); err != nil {
+			return err
+		}
+	}
+
+	restConfig, err := r.restConfig(ctx, deployer)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := r.helmConfiguration(ctx, restConfig, namespace)
+	if err != nil {
+		return err
+	}
+
+	if _, err := action.NewUninstall(cfg).Run(name); err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return fmt.Errorf("failed to uninstall helm release %s/%s: %w", namespace, name, err)
+	}
+
+	log.FromContext(ctx).Info("uninstalled helm release", "release", name, "namespace", namespace)
+
+	return nil
+}
+
+// helmReleaseKey returns the name and namespace of the Helm release as specified by the deployer.
+func helmReleaseKey(deployer *deliveryv1alpha1.Deployer) (string, string, error) {
+	spec := ptr.Deref(deployer.Spec.Helm, deliveryv1alpha1.HelmSpec{})
+
+	name := spec.ReleaseName
+	if name == "" {
+		name = deployer.GetName()
+	}
+
+	namespace := spec.Namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = resourceNamespace(deployer); err != nil {
+			return "", "", fmt.Errorf("failed to determine namespace of the helm release: %w", err)
+		}
+	}
+
+	return name, namespace, nil
+}
+
+// helmConfiguration returns the configuration for Helm actions on releases in the namespace with the given rest config.
+func (r *Reconciler) helmConfiguration(ctx context.Context, config *rest.Config, namespace string) (*action.Configuration, error) {
+	logger := log.FromContext(ctx).V(deliveryv1alpha1.LevelDebug)
+
+	cfg := &action.Configuration{}
+	if err := cfg.Init(&restClientGetter{config: config, namespace: namespace}, namespace, helmStorageDriver,
+		func(format string, v ...any) {
+			logger.Info(fmt.Sprintf(format, v...))
+		}); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// restClientGetter provides the clients Helm requires from the rest config of the controller instead of a kubeconfig.
+type restClientGetter struct {
+	config    *rest.Config
+	namespace string
+}
+
+func (g *restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return rest.CopyConfig(g.config), nil
+}
+
+func (g *restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+func (g *restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient), nil
+}
+
+func (g *restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	// Helm only uses the loader to determine the default namespace of the objects of a release.
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{},
+		&clientcmd.ConfigOverrides{Context: clientcmdapi.Context{Namespace: g.namespace}},
+	)
+}
@@ -0,0 +1,46 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("helmReleaseKey", func() {
+	It("defaults to the name of the deployer and the namespace of the resource", func() {
+		deployer := &v1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo"},
+			Spec: v1alpha1.DeployerSpec{
+				ResourceRef: v1alpha1.ObjectKey{Name: "resource", Namespace: "resource-namespace"},
+				Type:        v1alpha1.DeployerTypeHelm,
+			},
+		}
+
+		name, namespace, err := helmReleaseKey(deployer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("podinfo"))
+		Expect(namespace).To(Equal("resource-namespace"))
+	})
+
+	It("uses the spec even if another release is installed", func() {
+		deployer := &v1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo"},
+			Spec: v1alpha1.DeployerSpec{
+				ResourceRef: v1alpha1.ObjectKey{Name: "resource", Namespace: "resource-namespace"},
+				Type:        v1alpha1.DeployerTypeHelm,
+				Helm:        &v1alpha1.HelmSpec{ReleaseName: "renamed", Namespace: "moved"},
+			},
+			Status: v1alpha1.DeployerStatus{
+				Helm: &v1alpha1.HelmReleaseStatus{Name: "podinfo", Namespace: "resource-namespace"},
+			},
+		}
+
+		name, namespace, err := helmReleaseKey(deployer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("renamed"))
+		Expect(namespace).To(Equal("moved"))
+	})
+})
@@ -0,0 +1,113 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// serviceAccountUsername returns the username under which the API server authenticates the service account.
+func serviceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// resourceNamespace returns the namespace of the first referenced resource of the deployer. The service account and
+// the kubeconfig of the deployer are looked up in this namespace.
+func resourceNamespace(deployer *deliveryv1alpha1.Deployer) (string, error) {
+	namespace := deployer.GetResources()[0].ResourceRef.Namespace
+	if namespace == "" {
+		namespace = deployer.GetNamespace()
+	}
+	if namespace == "" {
+		return "", errors.New("namespace must be specified by the resource reference")
+	}
+
+	return namespace, nil
+}
+
+// serviceAccountName returns the name of the service account the deployer impersonates. Deployers that do not specify
+// a service account impersonate the default service account of the reconciler, unless they deploy to a remote cluster.
+func (r *Reconciler) serviceAccountName(deployer *deliveryv1alpha1.Deployer) string {
+	if deployer.Spec.ServiceAccountName != "" || deployer.Spec.KubeConfig != nil {
+		return deployer.Spec.ServiceAccountName
+	}
+
+	return r.DefaultServiceAccount
+}
+
+// restConfig returns the rest config to deploy the objects of the deployer with. If the deployer specifies a
+// kubeconfig, the objects are deployed to the remote cluster with the identity of the kubeconfig. Otherwise, the
+// service account (see serviceAccountName) in the namespace of the first referenced resource is impersonated.
+func (r *Reconciler) restConfig(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (*rest.Config, error) {
+	if deployer.Spec.KubeConfig != nil {
+		// A local service account does not exist in the remote cluster, the identity is defined by the kubeconfig.
+		if deployer.Spec.ServiceAccountName != "" {
+			return nil, errors.New("service accounts cannot be impersonated in remote clusters")
+		}
+
+		return r.kubeConfig(ctx, deployer)
+	}
+
+	serviceAccountName := r.serviceAccountName(deployer)
+	if serviceAccountName == "" {
+		return r.RESTConfig, nil
+	}
+
+	namespace, err := resourceNamespace(deployer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine namespace of the service account: %w", err)
+	}
+
+	cfg := rest.CopyConfig(r.RESTConfig)
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: serviceAccountUsername(namespace, serviceAccountName),
+	}
+
+	return cfg, nil
+}
+
+// deployClient returns the client to deploy the objects of the deployer with. Without a service account to
+// impersonate and a remote cluster, the client of the controller is used.
+func (r *Reconciler) deployClient(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (client.Client, error) {
+	if r.serviceAccountName(deployer) == "" && deployer.Spec.KubeConfig == nil {
+		return r.Client, nil
+	}
+
+	cfg, err := r.restConfig(ctx, deployer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The mapper of the controller only knows the kinds of its own cluster
+	opts := client.Options{Scheme: r.Scheme}
+	if deployer.Spec.KubeConfig == nil {
+		opts.Mapper = r.RESTMapper()
+	}
+
+	clnt, err := client.New(cfg, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client to deploy objects: %w", err)
+	}
+
+	return clnt, nil
+}
+
+// failureReason returns the condition reason for an error of the API server. Errors that are not caused by a conflict
+// or missing permissions are reported with the fallback reason.
+func failureReason(err error, fallback string) string {
+	switch {
+	case apierrors.IsConflict(err):
+		return deliveryv1alpha1.ApplyConflictReason
+	case apierrors.IsForbidden(err):
+		return deliveryv1alpha1.AccessDeniedReason
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,59 @@
+package deployer
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("restConfig", func() {
+	reconciler := &Reconciler{RESTConfig: &rest.Config{Host: "https://local.example.com"}}
+
+	deployer := func(serviceAccountName string, kubeConfig *v1alpha1.KubeConfigReference) *v1alpha1.Deployer {
+		return &v1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployer"},
+			Spec: v1alpha1.DeployerSpec{
+				ResourceRef:        v1alpha1.ObjectKey{Name: "resource", Namespace: "resource-namespace"},
+				ServiceAccountName: serviceAccountName,
+				KubeConfig:         kubeConfig,
+			},
+		}
+	}
+
+	It("impersonates the service account in the namespace of the resource", func(ctx SpecContext) {
+		cfg, err := reconciler.restConfig(ctx, deployer("deployer", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:resource-namespace:deployer"))
+		Expect(reconciler.RESTConfig.Impersonate.UserName).To(BeEmpty())
+	})
+
+	It("impersonates the default service account if the deployer does not specify one", func(ctx SpecContext) {
+		reconciler := &Reconciler{RESTConfig: reconciler.RESTConfig, DefaultServiceAccount: "default"}
+		cfg, err := reconciler.restConfig(ctx, deployer("", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Impersonate.UserName).To(Equal("system:serviceaccount:resource-namespace:default"))
+
+		By("not impersonating the default service account in remote clusters")
+		Expect(reconciler.serviceAccountName(deployer("", &v1alpha1.KubeConfigReference{
+			SecretRef: meta.SecretKeyReference{Name: "kubeconfig"},
+		}))).To(BeEmpty())
+	})
+
+	It("deploys with the permissions of the controller without a default service account", func(ctx SpecContext) {
+		cfg, err := reconciler.restConfig(ctx, deployer("", nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).To(BeIdenticalTo(reconciler.RESTConfig))
+	})
+
+	It("rejects impersonating service accounts in remote clusters", func(ctx SpecContext) {
+		_, err := reconciler.restConfig(ctx, deployer("deployer", &v1alpha1.KubeConfigReference{
+			SecretRef: meta.SecretKeyReference{Name: "kubeconfig"},
+		}))
+		Expect(err).To(MatchError(ContainSubstring("cannot be impersonated in remote clusters")))
+	})
+})
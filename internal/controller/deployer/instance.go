@@ -0,0 +1,208 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// instanceStateActive is the state kro reports for an instance whose resources were all reconciled.
+	instanceStateActive = "ACTIVE"
+
+	// instanceSyncedCondition is the condition kro sets on an instance once it reconciled the instance.
+	instanceSyncedCondition = "InstanceSynced"
+)
+
+// instanceObject returns the instance of the custom resource generated by the resource graph definition of the
+// deployer. Its spec consists of the inline values merged with the values of the referenced config maps and secrets.
+// If the deployer does not configure an instance, no object is returned.
+func (r *Reconciler) instanceObject(
+	ctx context.Context,
+	deployer *deliveryv1alpha1.Deployer,
+	objs []*unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	spec := deployer.Spec.Instance
+	if spec == nil {
+		return nil, nil
+	}
+
+	var rgd *krov1alpha1.ResourceGraphDefinition
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() != rgdGroupKind {
+			continue
+		}
+
+		rgd = &krov1alpha1.ResourceGraphDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rgd); err != nil {
+			return nil, fmt.Errorf("failed to convert resource graph definition: %w", err)
+		}
+
+		break
+	}
+
+	if rgd == nil {
+		return nil, errors.New("no resource graph definition to create the instance of")
+	}
+
+	gvk, ok := instanceGroupVersionKind(rgd)
+	if !ok {
+		return nil, fmt.Errorf("resource graph definition %s does not define the kind of its instances", rgd.GetName())
+	}
+
+	values := map[string]any{}
+	if spec.Values != nil && len(spec.Values.Raw) > 0 {
+		if err := json.Unmarshal(spec.Values.Raw, &values); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal inline values of the instance: %w", err)
+		}
+	}
+
+	refValues, err := r.getValues(ctx, deployer, spec.ValuesFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	name := spec.Name
+	if name == "" {
+		name = deployer.GetName()
+	}
+
+	namespace := spec.Namespace
+	if namespace == "" {
+		if namespace, err = resourceNamespace(deployer); err != nil {
+			return nil, fmt.Errorf("failed to determine namespace of the instance: %w", err)
+		}
+	}
+
+	instance := &unstructured.Unstructured{Object: map[string]any{"spec": mergeValues(values, refValues)}}
+	instance.SetGroupVersionKind(gvk)
+	instance.SetNamespace(namespace)
+	instance.SetName(name)
+
+	return instance, nil
+}
+
+// instanceEntry returns the inventory entry of the instance reported in the status of the deployer. It returns false
+// if the deployer did not create an instance.
+func instanceEntry(deployer *deliveryv1alpha1.Deployer) (deliveryv1alpha1.InventoryEntry, bool) {
+	instance := deployer.Status.Instance
+	if instance == nil {
+		return deliveryv1alpha1.InventoryEntry{}, false
+	}
+
+	return deliveryv1alpha1.InventoryEntry{
+		APIVersion: instance.APIVersion,
+		Kind:       instance.Kind,
+		Namespace:  instance.Namespace,
+		Name:       instance.Name,
+	}, true
+}
+
+// propagateInstanceStatus copies the state and status of the live instance into the status of the deployer and sets
+// the InstanceReady condition accordingly. It returns true if kro reconciled the instance successfully.
+func propagateInstanceStatus(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	instance *unstructured.Unstructured,
+) (bool, error) {
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(instance.GroupVersionKind())
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(instance), live); err != nil {
+		return false, fmt.Errorf("failed to get instance %s: %w", objectRef(instance), err)
+	}
+
+	instanceStatus, err := newInstanceStatus(live)
+	if err != nil {
+		return false, err
+	}
+	deployer.Status.Instance = instanceStatus
+
+	setInstanceReadyCondition(deployer, live)
+
+	return conditions.IsTrue(deployer, deliveryv1alpha1.InstanceReadyCondition), nil
+}
+
+// newInstanceStatus returns the status of the deployer reporting the state of the instance. The state and conditions
+// of the instance are omitted from the copied status, as they are reflected by the InstanceReady condition.
+func newInstanceStatus(instance *unstructured.Unstructured) (*deliveryv1alpha1.InstanceStatus, error) {
+	instanceStatus := &deliveryv1alpha1.InstanceStatus{
+		APIVersion: instance.GetAPIVersion(),
+		Kind:       instance.GetKind(),
+		Namespace:  instance.GetNamespace(),
+		Name:       instance.GetName(),
+	}
+
+	fields, _, err := unstructured.NestedMap(instance.Object, "status")
+	if err != nil {
+		return nil, fmt.Errorf("invalid status of instance %s: %w", objectRef(instance), err)
+	}
+
+	instanceStatus.State, _ = fields["state"].(string)
+	delete(fields, "state")
+	delete(fields, "conditions")
+
+	if len(fields) > 0 {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal status of instance %s: %w", objectRef(instance), err)
+		}
+
+		instanceStatus.Status = &apiextensionsv1.JSON{Raw: raw}
+	}
+
+	return instanceStatus, nil
+}
+
+// setInstanceReadyCondition sets the InstanceReady condition of the deployer from the state and the InstanceSynced
+// condition of the instance.
+func setInstanceReadyCondition(deployer *deliveryv1alpha1.Deployer, instance *unstructured.Unstructured) {
+	state, _, _ := unstructured.NestedString(instance.Object, "status", "state")
+	synced, message := instanceCondition(instance, instanceSyncedCondition)
+
+	switch {
+	case state == instanceStateActive && synced == "True":
+		conditions.MarkTrue(deployer, deliveryv1alpha1.InstanceReadyCondition, meta.SucceededReason,
+			"Instance %s is active", objectRef(instance))
+	case synced == "False":
+		conditions.MarkFalse(deployer, deliveryv1alpha1.InstanceReadyCondition, deliveryv1alpha1.InstanceNotReadyReason,
+			"Instance %s is in state %s: %s", objectRef(instance), state, message)
+	case state == "":
+		conditions.MarkUnknown(deployer, deliveryv1alpha1.InstanceReadyCondition, deliveryv1alpha1.InstanceNotReadyReason,
+			"Waiting for kro to reconcile instance %s", objectRef(instance))
+	default:
+		conditions.MarkFalse(deployer, deliveryv1alpha1.InstanceReadyCondition, deliveryv1alpha1.InstanceNotReadyReason,
+			"Instance %s is in state %s", objectRef(instance), state)
+	}
+}
+
+// instanceCondition returns the status and message of the condition of the instance. An empty status is returned if
+// the instance does not have the condition.
+func instanceCondition(instance *unstructured.Unstructured, conditionType string) (string, string) {
+	conds, _, _ := unstructured.NestedSlice(instance.Object, "status", "conditions")
+	for _, c := range conds {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != conditionType {
+			continue
+		}
+
+		status, _ := cond["status"].(string)
+		message, _ := cond["message"].(string)
+
+		return status, message
+	}
+
+	return "", ""
+}
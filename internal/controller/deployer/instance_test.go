@@ -0,0 +1,70 @@
+package deployer
+
+import (
+	"github.com/fluxcd/pkg/runtime/conditions"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("instance status", func() {
+	instance := func(status map[string]any) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "WebApp",
+			"metadata":   map[string]any{"namespace": "default", "name": "webapp"},
+		}}
+		if status != nil {
+			obj.Object["status"] = status
+		}
+
+		return obj
+	}
+
+	synced := func(status, message string) []any {
+		return []any{map[string]any{"type": instanceSyncedCondition, "status": status, "message": message}}
+	}
+
+	It("copies the status without state and conditions", func() {
+		instanceStatus, err := newInstanceStatus(instance(map[string]any{
+			"state":      instanceStateActive,
+			"conditions": synced("True", ""),
+			"endpoint":   "https://webapp.example.com",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceStatus.Kind).To(Equal("WebApp"))
+		Expect(instanceStatus.Namespace).To(Equal("default"))
+		Expect(instanceStatus.Name).To(Equal("webapp"))
+		Expect(instanceStatus.State).To(Equal(instanceStateActive))
+		Expect(string(instanceStatus.Status.Raw)).To(MatchJSON(`{"endpoint":"https://webapp.example.com"}`))
+	})
+
+	It("omits an empty status", func() {
+		instanceStatus, err := newInstanceStatus(instance(map[string]any{"state": "IN_PROGRESS"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instanceStatus.Status).To(BeNil())
+	})
+
+	DescribeTable("sets the InstanceReady condition",
+		func(status map[string]any, expected metav1.ConditionStatus, message string) {
+			deployer := &deliveryv1alpha1.Deployer{}
+			setInstanceReadyCondition(deployer, instance(status))
+
+			cond := conditions.Get(deployer, deliveryv1alpha1.InstanceReadyCondition)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(expected))
+			Expect(cond.Message).To(ContainSubstring(message))
+		},
+		Entry("active and synced", map[string]any{"state": instanceStateActive, "conditions": synced("True", "")},
+			metav1.ConditionTrue, "is active"),
+		Entry("in progress", map[string]any{"state": "IN_PROGRESS", "conditions": synced("True", "")},
+			metav1.ConditionFalse, "in state IN_PROGRESS"),
+		Entry("not synced", map[string]any{"state": "ERROR", "conditions": synced("False", "invalid spec")},
+			metav1.ConditionFalse, "invalid spec"),
+		Entry("not reconciled yet", nil, metav1.ConditionUnknown, "Waiting for kro"),
+	)
+})
@@ -0,0 +1,127 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// newInventory returns the inventory entries of the applied objects.
+func newInventory(objs []*unstructured.Unstructured) []deliveryv1alpha1.InventoryEntry {
+	inventory := make([]deliveryv1alpha1.InventoryEntry, 0, len(objs))
+	for _, obj := range objs {
+		inventory = append(inventory, inventoryEntry(obj))
+	}
+
+	return inventory
+}
+
+// inventoryEntry returns the inventory entry identifying the object.
+func inventoryEntry(obj *unstructured.Unstructured) deliveryv1alpha1.InventoryEntry {
+	return deliveryv1alpha1.InventoryEntry{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+// staleEntries returns the entries of the old inventory that are not part of the new inventory in reverse order, so
+// that objects are deleted in the opposite order they were applied in. Entries are compared by group, kind, namespace,
+// and name to not delete objects whose api version changed.
+func staleEntries(oldInventory, newInventory []deliveryv1alpha1.InventoryEntry) []deliveryv1alpha1.InventoryEntry {
+	var stale []deliveryv1alpha1.InventoryEntry
+	for _, entry := range slices.Backward(oldInventory) {
+		if !slices.ContainsFunc(newInventory, func(e deliveryv1alpha1.InventoryEntry) bool {
+			return sameObject(entry, e)
+		}) {
+			stale = append(stale, entry)
+		}
+	}
+
+	return stale
+}
+
+func sameObject(a, b deliveryv1alpha1.InventoryEntry) bool {
+	gkA := schema.FromAPIVersionAndKind(a.APIVersion, a.Kind).GroupKind()
+	gkB := schema.FromAPIVersionAndKind(b.APIVersion, b.Kind).GroupKind()
+
+	return gkA == gkB && a.Namespace == b.Namespace && a.Name == b.Name
+}
+
+// prune deletes the objects of the given inventory entries. Objects that do not exist anymore or that are not
+// controlled by the deployer are skipped.
+func (r *Reconciler) prune(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	entries []deliveryv1alpha1.InventoryEntry,
+) error {
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	for _, entry := range entries {
+		obj, err := controlledObject(ctx, clnt, deployer, entry)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+		if obj == nil {
+			continue
+		}
+
+		if err := clnt.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", objectRef(obj), err))
+
+			continue
+		}
+
+		logger.Info("pruned object", "object", objectRef(obj))
+	}
+
+	return errors.Join(errs...)
+}
+
+// controlledObject returns the object of the inventory entry if it is controlled by the deployer. If the object does
+// not exist anymore or is not controlled by the deployer, no object is returned.
+func controlledObject(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	entry deliveryv1alpha1.InventoryEntry,
+) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(entry.APIVersion)
+	obj.SetKind(entry.Kind)
+	obj.SetNamespace(entry.Namespace)
+	obj.SetName(entry.Name)
+
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s: %w", objectRef(obj), err)
+	}
+
+	if owner := controllerOf(obj); owner == nil || owner.UID != deployer.GetUID() {
+		log.FromContext(ctx).Info("skip object as it is not controlled by the deployer", "object", objectRef(obj))
+
+		return nil, nil
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,34 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("staleEntries", func() {
+	first := v1alpha1.InventoryEntry{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "first"}
+	second := v1alpha1.InventoryEntry{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "second"}
+	third := v1alpha1.InventoryEntry{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "third"}
+
+	It("returns the removed entries in reverse order", func() {
+		Expect(staleEntries(
+			[]v1alpha1.InventoryEntry{first, second, third},
+			[]v1alpha1.InventoryEntry{first},
+		)).To(Equal([]v1alpha1.InventoryEntry{third, second}))
+	})
+
+	It("does not return entries whose api version changed", func() {
+		thirdUpdated := third
+		thirdUpdated.APIVersion = "apps/v1beta2"
+		Expect(staleEntries(
+			[]v1alpha1.InventoryEntry{third},
+			[]v1alpha1.InventoryEntry{thirdUpdated},
+		)).To(BeEmpty())
+	})
+
+	It("returns nothing without a previous inventory", func() {
+		Expect(staleEntries(nil, []v1alpha1.InventoryEntry{first})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,165 @@
+package deployer
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// kustomizeSourceDir is the directory the resource is extracted to for the build.
+	kustomizeSourceDir = "/source"
+	// kustomizeOverlayDir is the directory of the generated kustomization that adds the patches and images of the
+	// deployer to the kustomization of the resource.
+	kustomizeOverlayDir = "/overlay"
+)
+
+// buildKustomization extracts the (compressed) tar archive and builds the kustomization at the path of the spec with
+// the patches and images of the spec. If decryption keys are given, the encrypted YAML files of the archive are
+// decrypted before the build, as kustomize changes the documents, which breaks their integrity check. It returns the
+// resulting multi-document manifest.
+func buildKustomization(
+	archive []byte,
+	spec deliveryv1alpha1.KustomizeSpec,
+	decryption *decryptionKeys,
+) ([]byte, error) {
+	fs := filesys.MakeFsInMemory()
+	if err := extractTar(archive, fs, kustomizeSourceDir); err != nil {
+		return nil, err
+	}
+
+	if decryption != nil {
+		if err := decryptSources(fs, kustomizeSourceDir, decryption); err != nil {
+			return nil, err
+		}
+	}
+
+	kustomizationDir := path.Join(kustomizeSourceDir, path.Clean("/"+spec.Path))
+	overlay, err := yaml.Marshal(kustomizeOverlay(path.Join("..", kustomizationDir), spec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal kustomization: %w", err)
+	}
+
+	if err := fs.WriteFile(path.Join(kustomizeOverlayDir, "kustomization.yaml"), overlay); err != nil {
+		return nil, fmt.Errorf("failed to write kustomization: %w", err)
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, kustomizeOverlayDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization: %w", err)
+	}
+
+	return resources.AsYaml()
+}
+
+// kustomizeOverlay returns a kustomization that adds the patches and images of the spec to the kustomization in the
+// directory.
+func kustomizeOverlay(dir string, spec deliveryv1alpha1.KustomizeSpec) *types.Kustomization {
+	kustomization := &types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources: []string{dir},
+		Patches:   kustomizePatches(spec.Patches),
+	}
+
+	for _, image := range spec.Images {
+		kustomization.Images = append(kustomization.Images, types.Image{
+			Name:    image.Name,
+			NewName: image.NewName,
+			NewTag:  image.NewTag,
+			Digest:  image.Digest,
+		})
+	}
+
+	return kustomization
+}
+
+// kustomizePatches converts the patches of the deployer into kustomize patches.
+func kustomizePatches(patches []deliveryv1alpha1.KustomizePatch) []types.Patch {
+	result := make([]types.Patch, 0, len(patches))
+	for _, patch := range patches {
+		p := types.Patch{Patch: patch.Patch}
+		if target := patch.Target; target != nil {
+			p.Target = &types.Selector{
+				ResId: resid.ResId{
+					Gvk:       resid.Gvk{Group: target.Group, Version: target.Version, Kind: target.Kind},
+					Name:      target.Name,
+					Namespace: target.Namespace,
+				},
+				LabelSelector:      target.LabelSelector,
+				AnnotationSelector: target.AnnotationSelector,
+			}
+		}
+		result = append(result, p)
+	}
+
+	return result
+}
+
+// decryptSources decrypts the SOPS encrypted documents of the YAML files in the directory of the file system in place.
+func decryptSources(fs filesys.FileSystem, dir string, k *decryptionKeys) error {
+	return fs.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || (path.Ext(name) != ".yaml" && path.Ext(name) != ".yml") {
+			return nil
+		}
+
+		data, err := fs.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		decrypted, err := decryptManifest(data, k)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", strings.TrimPrefix(name, dir+"/"), err)
+		}
+
+		return fs.WriteFile(name, decrypted)
+	})
+}
+
+// extractTar writes the directories and regular files of the (compressed) tar archive into the directory of the file
+// system.
+func extractTar(archive []byte, fs filesys.FileSystem, dir string) error {
+	return walkTar(archive, func(header *tar.Header, content io.Reader) (bool, error) {
+		name := path.Clean(header.Name)
+		if name == ".." || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			return false, fmt.Errorf("invalid path %s in archive", header.Name)
+		}
+
+		target := path.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := fs.MkdirAll(target); err != nil {
+				return false, fmt.Errorf("failed to create directory %s: %w", name, err)
+			}
+		case tar.TypeReg:
+			data, err := io.ReadAll(content)
+			if err != nil {
+				return false, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+
+			if err := fs.WriteFile(target, data); err != nil {
+				return false, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+
+		return false, nil
+	})
+}
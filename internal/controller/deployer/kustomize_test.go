@@ -0,0 +1,112 @@
+package deployer
+
+import (
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	sopsage "github.com/getsops/sops/v3/age"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("buildKustomization", func() {
+	archive := tarGz(map[string][]byte{
+		"deploy/kustomization.yaml": []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: podinfo
+resources:
+- deployment.yaml
+`),
+		"deploy/deployment.yaml": []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.7.0
+`),
+	})
+
+	It("builds the kustomization at the path", func() {
+		manifest, err := buildKustomization(archive, v1alpha1.KustomizeSpec{Path: "deploy"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(manifest)).To(ContainSubstring("namespace: podinfo"))
+		Expect(string(manifest)).To(ContainSubstring("image: ghcr.io/stefanprodan/podinfo:6.7.0"))
+	})
+
+	It("applies the patches and images of the spec", func() {
+		manifest, err := buildKustomization(archive, v1alpha1.KustomizeSpec{
+			Path: "deploy",
+			Patches: []v1alpha1.KustomizePatch{{
+				Patch: `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`,
+				Target: &v1alpha1.KustomizeSelector{
+					Kind: "Deployment",
+					Name: "podinfo",
+				},
+			}},
+			Images: []v1alpha1.KustomizeImage{{
+				Name:    "ghcr.io/stefanprodan/podinfo",
+				NewName: "registry.local/podinfo",
+				NewTag:  "6.7.1",
+			}},
+		}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(manifest)).To(ContainSubstring("replicas: 3"))
+		Expect(string(manifest)).To(ContainSubstring("image: registry.local/podinfo:6.7.1"))
+	})
+
+	It("decrypts the encrypted documents before the build", func() {
+		identity, err := age.GenerateX25519Identity()
+		Expect(err).NotTo(HaveOccurred())
+
+		archive := tarGz(map[string][]byte{
+			"deploy/kustomization.yaml": []byte(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: podinfo
+commonLabels:
+  app: podinfo
+resources:
+- secret.yaml
+`),
+			"deploy/secret.yaml": encryptDocument([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: podinfo
+stringData:
+  password: secret-value
+`), identity),
+		})
+		spec := v1alpha1.KustomizeSpec{
+			Path: "deploy",
+			Patches: []v1alpha1.KustomizePatch{{
+				Patch:  `[{"op": "add", "path": "/metadata/annotations", "value": {"env": "prod"}}]`,
+				Target: &v1alpha1.KustomizeSelector{Kind: "Secret"},
+			}},
+		}
+
+		manifest, err := buildKustomization(archive, spec, &decryptionKeys{age: sopsage.ParsedIdentities{identity}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(manifest)).To(ContainSubstring("password: secret-value"))
+		Expect(string(manifest)).To(ContainSubstring("namespace: podinfo"))
+		Expect(string(manifest)).To(ContainSubstring("env: prod"))
+		Expect(string(manifest)).NotTo(ContainSubstring("sops:"))
+
+		_, err = buildKustomization(archive, spec, &decryptionKeys{})
+		Expect(err).To(MatchError(ContainSubstring("failed to decrypt deploy/secret.yaml")))
+	})
+
+	It("fails for a path without kustomization", func() {
+		_, err := buildKustomization(archive, v1alpha1.KustomizeSpec{Path: "missing"}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects archives escaping the root", func() {
+		_, err := buildKustomization(tarGz(map[string][]byte{"../kustomization.yaml": nil}), v1alpha1.KustomizeSpec{}, nil)
+		Expect(err).To(MatchError(ContainSubstring("invalid path")))
+	})
+})
@@ -0,0 +1,198 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// rgdGroupKind is the group and kind of kro resource graph definitions.
+var rgdGroupKind = krov1alpha1.GroupVersion.WithKind("ResourceGraphDefinition").GroupKind()
+
+// isResourceGraphDefinitionType returns true if the deployer deploys a kro resource graph definition. This is the
+// default if no type is specified.
+func isResourceGraphDefinitionType(deployer *deliveryv1alpha1.Deployer) bool {
+	return deployer.Spec.Type == "" || deployer.Spec.Type == deliveryv1alpha1.DeployerTypeResourceGraphDefinition
+}
+
+// decodeObjects decodes the manifest into the objects to deploy according to the type of the deployer.
+func decodeObjects(deployerType deliveryv1alpha1.DeployerType, manifest []byte) ([]*unstructured.Unstructured, error) {
+	switch deployerType {
+	case deliveryv1alpha1.DeployerTypeResourceGraphDefinition, "":
+		return decodeResourceGraphDefinition(manifest)
+	case deliveryv1alpha1.DeployerTypeManifest, deliveryv1alpha1.DeployerTypeKustomize:
+		return decodeManifest(manifest)
+	default:
+		return nil, fmt.Errorf("unsupported deployer type: %s", deployerType)
+	}
+}
+
+// decodeResourceGraphDefinition decodes the manifest into a single resource graph definition.
+func decodeResourceGraphDefinition(manifest []byte) ([]*unstructured.Unstructured, error) {
+	var rgd krov1alpha1.ResourceGraphDefinition
+	if err := yaml.Unmarshal(manifest, &rgd); err != nil {
+		return nil, err
+	}
+
+	rgd.SetGroupVersionKind(krov1alpha1.GroupVersion.WithKind("ResourceGraphDefinition"))
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&rgd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert resource graph definition: %w", err)
+	}
+
+	return []*unstructured.Unstructured{{Object: obj}}, nil
+}
+
+// decodeManifest decodes a (multi-document) YAML or JSON manifest into its objects. Empty documents are skipped and
+// lists are flattened into their items.
+func decodeManifest(manifest []byte) ([]*unstructured.Unstructured, error) {
+	const bufferSize = 4096
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), bufferSize)
+
+	var objs []*unstructured.Unstructured
+	for i := 0; ; i++ {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("failed to decode document %d: %w", i, err)
+		}
+
+		if len(bytes.TrimSpace(raw.Raw)) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return nil, fmt.Errorf("failed to decode object in document %d: %w", i, err)
+		}
+
+		if !obj.IsList() {
+			objs = append(objs, obj)
+
+			continue
+		}
+
+		list, err := obj.ToList()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list in document %d: %w", i, err)
+		}
+
+		for _, item := range list.Items {
+			objs = append(objs, &item)
+		}
+	}
+
+	if len(objs) == 0 {
+		return nil, errors.New("manifest does not contain any objects")
+	}
+
+	return objs, nil
+}
+
+// applyObject applies the object with server-side apply and sets the deployer as its controller. Fields managed by
+// another field manager are only taken over if force is set.
+func (r *Reconciler) applyObject(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	desired *unstructured.Unstructured,
+	force bool,
+	opts ...client.PatchOption,
+) (*unstructured.Unstructured, error) {
+	obj, err := prepareObject(clnt, deployer, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, client.FieldOwner(fieldManager))
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+
+	if err := clnt.Patch(ctx, obj, client.Apply, opts...); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// prepareObject returns a copy of the desired object that can be applied server-side by the deployer. As the deployer
+// is cluster-scoped, namespaced objects without namespace are deployed into the namespace of the first referenced
+// resource.
+func prepareObject(
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	desired *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	namespaced, err := clnt.IsObjectNamespaced(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine scope: %w", err)
+	}
+
+	obj := desired.DeepCopy()
+	switch {
+	case !namespaced:
+		obj.SetNamespace("")
+	case obj.GetNamespace() == "":
+		namespace, err := resourceNamespace(deployer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to default namespace of %s: %w", objectRef(obj), err)
+		}
+
+		obj.SetNamespace(namespace)
+	}
+
+	// Server-side apply rejects server-populated fields and the deployer must not own the status.
+	unstructured.RemoveNestedField(obj.Object, "status")
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+
+	// Objects in remote clusters cannot reference the deployer as owner
+	if deployer.Spec.KubeConfig != nil {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[deployerNameAnnotation] = deployer.GetName()
+		annotations[deployerUIDAnnotation] = string(deployer.GetUID())
+		obj.SetAnnotations(annotations)
+
+		return obj, nil
+	}
+
+	if err := controllerutil.SetControllerReference(deployer, obj, clnt.Scheme()); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	return obj, nil
+}
+
+// objectRef returns a human-readable reference of the object for logs and messages.
+func objectRef(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+	}
+
+	return fmt.Sprintf("%s/%s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
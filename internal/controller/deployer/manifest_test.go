@@ -0,0 +1,104 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("decodeObjects", func() {
+	It("decodes a multi-document manifest and flattens lists", func() {
+		manifest := []byte(`apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: first
+  - apiVersion: v1
+    kind: Secret
+    metadata:
+      name: second
+---
+# only a comment
+---
+{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "third"}, "spec": {"replicas": 1}}
+`)
+
+		objs, err := decodeObjects(v1alpha1.DeployerTypeManifest, manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(3))
+		Expect(objs[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(objs[1].GetKind()).To(Equal("Secret"))
+		Expect(objs[2].GetKind()).To(Equal("Deployment"))
+		Expect(objs[2].Object).To(HaveKeyWithValue("spec", HaveKeyWithValue("replicas", int64(1))))
+	})
+
+	It("fails for objects without kind", func() {
+		_, err := decodeObjects(v1alpha1.DeployerTypeManifest, []byte(`apiVersion: v1
+metadata:
+  name: no-kind`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails for an empty manifest", func() {
+		_, err := decodeObjects(v1alpha1.DeployerTypeManifest, []byte("---\n"))
+		Expect(err).To(MatchError(ContainSubstring("does not contain any objects")))
+	})
+
+	It("decodes a resource graph definition by default", func() {
+		objs, err := decodeObjects("", []byte(`apiVersion: kro.run/v1alpha1
+kind: ResourceGraphDefinition
+metadata:
+  name: rgd
+spec:
+  schema:
+    apiVersion: v1alpha1
+    kind: SomeKind`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(1))
+		Expect(objs[0].GetKind()).To(Equal("ResourceGraphDefinition"))
+		Expect(objs[0].GetName()).To(Equal("rgd"))
+	})
+})
+
+var _ = Describe("prepareObject", func() {
+	deployer := &v1alpha1.Deployer{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployer", UID: "uid"},
+		Spec: v1alpha1.DeployerSpec{
+			ResourceRef: v1alpha1.ObjectKey{Name: "resource", Namespace: "resource-namespace"},
+		},
+	}
+
+	object := func(kind, namespace string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind(kind)
+		obj.SetName("object")
+		obj.SetNamespace(namespace)
+
+		return obj
+	}
+
+	It("deploys namespaced objects without namespace into the namespace of the resource", func() {
+		obj, err := prepareObject(k8sClient, deployer, object("ConfigMap", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.GetNamespace()).To(Equal("resource-namespace"))
+	})
+
+	It("keeps the namespace of namespaced objects", func() {
+		obj, err := prepareObject(k8sClient, deployer, object("ConfigMap", "other"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.GetNamespace()).To(Equal("other"))
+	})
+
+	It("removes the namespace of cluster-scoped objects", func() {
+		obj, err := prepareObject(k8sClient, deployer, object("Namespace", "other"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj.GetNamespace()).To(BeEmpty())
+	})
+})
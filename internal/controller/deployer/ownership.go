@@ -0,0 +1,122 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// deployerNameAnnotation and deployerUIDAnnotation identify the deployer controlling an object in a remote
+	// cluster, where the deployer cannot be referenced as owner.
+	deployerNameAnnotation = "delivery.ocm.software/deployer-name"
+	deployerUIDAnnotation  = "delivery.ocm.software/deployer-uid"
+)
+
+// controllerOf returns the reference of the controller of the object. Objects in remote clusters reference the
+// deployer controlling them by annotations instead of an owner reference.
+func controllerOf(obj metav1.Object) *metav1.OwnerReference {
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return owner
+	}
+
+	uid, ok := obj.GetAnnotations()[deployerUIDAnnotation]
+	if !ok {
+		return nil
+	}
+
+	return &metav1.OwnerReference{
+		APIVersion: deliveryv1alpha1.GroupVersion.String(),
+		Kind:       deliveryv1alpha1.KindDeployer,
+		Name:       obj.GetAnnotations()[deployerNameAnnotation],
+		UID:        types.UID(uid),
+		Controller: ptr.To(true),
+	}
+}
+
+// removeController removes the owner reference and the annotations of the controller from the object.
+func removeController(obj metav1.Object, uid types.UID) {
+	obj.SetOwnerReferences(slices.DeleteFunc(obj.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == uid
+	}))
+
+	annotations := obj.GetAnnotations()
+	if annotations[deployerUIDAnnotation] == string(uid) {
+		delete(annotations, deployerNameAnnotation)
+		delete(annotations, deployerUIDAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// checkOwnership returns the conflicts of objects that already exist and are controlled by another owner than the
+// deployer. If the deployer adopts objects, the other owner is removed as controller from such objects instead, unless
+// the deployer only dry-runs the apply.
+func (r *Reconciler) checkOwnership(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	objs []*unstructured.Unstructured,
+) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	var conflicts []string
+	for _, desired := range objs {
+		obj, err := prepareObject(clnt, deployer, desired)
+		if err != nil {
+			// Objects whose kind does not exist yet cannot be owned by anyone
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get %s: %w", objectRef(obj), err)
+		}
+
+		owner := controllerOf(live)
+		if owner == nil || owner.UID == deployer.GetUID() {
+			continue
+		}
+
+		if !deployer.Spec.AdoptObjects {
+			conflicts = append(conflicts, fmt.Sprintf("%s is controlled by %s %s", objectRef(live), owner.Kind, owner.Name))
+
+			continue
+		}
+
+		if deployer.Spec.Mode == deliveryv1alpha1.DeployerModeDryRun {
+			continue
+		}
+
+		patch := client.MergeFrom(live.DeepCopy())
+		removeController(live, owner.UID)
+		if err := clnt.Patch(ctx, live, patch); err != nil {
+			return nil, fmt.Errorf("failed to adopt %s: %w", objectRef(live), err)
+		}
+
+		logger.Info("adopted object", "object", objectRef(live), "previousOwner", fmt.Sprintf("%s/%s", owner.Kind, owner.Name))
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,38 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("controllerOf", func() {
+	It("returns the controller owner reference", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Deployer", Name: "local", UID: "local-uid", Controller: ptr.To(true)}})
+
+		Expect(controllerOf(obj).UID).To(BeEquivalentTo("local-uid"))
+	})
+
+	It("returns the deployer of the annotations", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{deployerNameAnnotation: "remote", deployerUIDAnnotation: "remote-uid"})
+
+		owner := controllerOf(obj)
+		Expect(owner.Kind).To(Equal(deliveryv1alpha1.KindDeployer))
+		Expect(owner.Name).To(Equal("remote"))
+		Expect(owner.UID).To(BeEquivalentTo("remote-uid"))
+
+		removeController(obj, owner.UID)
+		Expect(controllerOf(obj)).To(BeNil())
+	})
+
+	It("returns nil without controller", func() {
+		Expect(controllerOf(&unstructured.Unstructured{})).To(BeNil())
+	})
+})
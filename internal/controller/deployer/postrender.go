@@ -0,0 +1,123 @@
+package deployer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+const (
+	// postRenderDir is the directory of the generated kustomization that patches the objects.
+	postRenderDir = "/postrender"
+	// postRenderObjectsFile is the file in the post render directory containing the objects to patch.
+	postRenderObjectsFile = "objects.yaml"
+)
+
+// postRender applies the patches, common labels and common annotations of the spec to the objects. The objects are
+// patched with kustomize, so that the patches behave like the patches of a Kustomize deployer. It returns the patched
+// objects in their original order.
+func postRender(
+	objs []*unstructured.Unstructured,
+	spec deliveryv1alpha1.PostRenderSpec,
+) ([]*unstructured.Unstructured, error) {
+	manifest, err := encodeObjects(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	kustomization := &types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources:         []string{postRenderObjectsFile},
+		Patches:           kustomizePatches(spec.Patches),
+		CommonAnnotations: spec.CommonAnnotations,
+	}
+
+	// Selectors and templates are not labeled, as the selectors of existing objects are immutable
+	if len(spec.CommonLabels) > 0 {
+		kustomization.Labels = []types.Label{{Pairs: spec.CommonLabels}}
+	}
+
+	overlay, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal kustomization: %w", err)
+	}
+
+	fs := filesys.MakeFsInMemory()
+	if err := fs.WriteFile(path.Join(postRenderDir, postRenderObjectsFile), manifest); err != nil {
+		return nil, fmt.Errorf("failed to write objects: %w", err)
+	}
+
+	if err := fs.WriteFile(path.Join(postRenderDir, "kustomization.yaml"), overlay); err != nil {
+		return nil, fmt.Errorf("failed to write kustomization: %w", err)
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fs, postRenderDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch objects: %w", err)
+	}
+
+	patched, err := resources.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patched objects: %w", err)
+	}
+
+	patchedObjs, err := decodeManifest(patched)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal patched objects: %w", err)
+	}
+
+	return patchedObjs, nil
+}
+
+// encodeObjects encodes the objects into a multi-document YAML manifest.
+func encodeObjects(objs []*unstructured.Unstructured) ([]byte, error) {
+	var manifest bytes.Buffer
+	for _, obj := range objs {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", objectRef(obj), err)
+		}
+
+		manifest.WriteString("---\n")
+		manifest.Write(data)
+	}
+
+	return manifest.Bytes(), nil
+}
+
+// postRenderDigest returns the digest of the manifests of the revisions before they were decrypted and of the post
+// render spec, which together determine the patched objects. The patched objects themselves are not hashed, as the
+// digest is stored in the status and must not reveal decrypted data. It is empty if the deployer does not patch its
+// objects.
+func postRenderDigest(deployer *deliveryv1alpha1.Deployer, revisions []*resourceRevision) (string, error) {
+	if deployer.Spec.PostRender == nil {
+		return "", nil
+	}
+
+	spec, err := json.Marshal(deployer.Spec.PostRender)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal post render spec: %w", err)
+	}
+
+	hash := sha256.New()
+	for _, revision := range revisions {
+		hash.Write(revision.manifest)
+	}
+	hash.Write(spec)
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,124 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("postRender", func() {
+	var objs []*unstructured.Unstructured
+
+	BeforeEach(func() {
+		var err error
+		objs, err = decodeManifest([]byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: default
+data:
+  key: value
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  selector:
+    matchLabels:
+      app: podinfo
+  template:
+    metadata:
+      labels:
+        app: podinfo
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.7.0
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("applies strategic merge and JSON 6902 patches", func() {
+		patched, err := postRender(objs, v1alpha1.PostRenderSpec{
+			Patches: []v1alpha1.KustomizePatch{
+				{Patch: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: default
+spec:
+  template:
+    spec:
+      nodeSelector:
+        pool: edge
+`},
+				{
+					Patch:  `[{"op": "add", "path": "/data/env", "value": "prod"}]`,
+					Target: &v1alpha1.KustomizeSelector{Kind: "ConfigMap"},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(patched).To(HaveLen(2))
+		Expect(patched[0].GetKind()).To(Equal("ConfigMap"))
+		Expect(patched[0].Object["data"]).To(HaveKeyWithValue("env", "prod"))
+
+		nodeSelector, _, err := unstructured.NestedStringMap(patched[1].Object, "spec", "template", "spec", "nodeSelector")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodeSelector).To(HaveKeyWithValue("pool", "edge"))
+	})
+
+	It("adds common labels and annotations without changing selectors", func() {
+		patched, err := postRender(objs, v1alpha1.PostRenderSpec{
+			CommonLabels:      map[string]string{"env": "prod"},
+			CommonAnnotations: map[string]string{"team": "platform"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, obj := range patched {
+			Expect(obj.GetLabels()).To(HaveKeyWithValue("env", "prod"))
+			Expect(obj.GetAnnotations()).To(HaveKeyWithValue("team", "platform"))
+		}
+
+		selector, _, err := unstructured.NestedStringMap(patched[1].Object, "spec", "selector", "matchLabels")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector).To(Equal(map[string]string{"app": "podinfo"}))
+	})
+
+	It("fails for a patch of a missing object", func() {
+		_, err := postRender(objs, v1alpha1.PostRenderSpec{
+			Patches: []v1alpha1.KustomizePatch{{Patch: `apiVersion: v1
+kind: Secret
+metadata:
+  name: missing
+`}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("failed to patch objects")))
+	})
+})
+
+var _ = Describe("postRenderDigest", func() {
+	revisions := []*resourceRevision{{manifest: []byte("kind: ConfigMap")}}
+	deployer := func(spec *v1alpha1.PostRenderSpec) *v1alpha1.Deployer {
+		return &v1alpha1.Deployer{Spec: v1alpha1.DeployerSpec{PostRender: spec}}
+	}
+
+	It("is empty without post render patches", func() {
+		Expect(postRenderDigest(deployer(nil), revisions)).To(BeEmpty())
+	})
+
+	It("changes with the manifest and the post render spec", func() {
+		digest, err := postRenderDigest(deployer(&v1alpha1.PostRenderSpec{}), revisions)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(HavePrefix("sha256:"))
+
+		Expect(postRenderDigest(deployer(&v1alpha1.PostRenderSpec{}), []*resourceRevision{{manifest: []byte("kind: Secret")}})).
+			NotTo(Equal(digest))
+		Expect(postRenderDigest(deployer(&v1alpha1.PostRenderSpec{CommonLabels: map[string]string{"env": "prod"}}), revisions)).
+			NotTo(Equal(digest))
+	})
+})
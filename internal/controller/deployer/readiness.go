@@ -0,0 +1,76 @@
+package deployer
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kstatus "github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// notReadyObjects returns the references of the objects that do not exist or are not healthy yet.
+func notReadyObjects(ctx context.Context, clnt client.Client, objs []*unstructured.Unstructured) ([]string, error) {
+	var notReady []string
+	for _, obj := range objs {
+		result, err := objectHealth(ctx, clnt, obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Status != kstatus.CurrentStatus {
+			notReady = append(notReady, objectRef(obj))
+		}
+	}
+
+	return notReady, nil
+}
+
+// isObjectReady returns true if the status of the object reflects its current generation and its Ready condition is
+// true. Objects without Ready condition are ready once they exist. Resource graph definitions are ready once kro
+// accepted their current generation.
+func isObjectReady(obj *unstructured.Unstructured) bool {
+	if observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); found &&
+		observed != obj.GetGeneration() {
+		return false
+	}
+
+	if obj.GroupVersionKind().GroupKind() == rgdGroupKind {
+		rgd := &krov1alpha1.ResourceGraphDefinition{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rgd); err != nil {
+			return false
+		}
+
+		for _, mapping := range rgdConditions {
+			if status, _ := resourceGraphDefinitionCondition(rgd, mapping.rgd); status != metav1.ConditionTrue {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	status, found := conditionStatus(obj, "Ready")
+
+	return !found || status == "True"
+}
+
+// conditionStatus returns the status of the condition of the given type from the status of the object.
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]any)
+		if !ok || c["type"] != conditionType {
+			continue
+		}
+
+		status, _ := c["status"].(string)
+
+		return status, true
+	}
+
+	return "", false
+}
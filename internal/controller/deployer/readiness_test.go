@@ -0,0 +1,75 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("isObjectReady", func() {
+	object := func(generation int64, status map[string]any) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.com/v1",
+			"kind":       "Example",
+		}}
+		obj.SetGeneration(generation)
+		if status != nil {
+			obj.Object["status"] = status
+		}
+
+		return obj
+	}
+
+	It("considers objects without status ready", func() {
+		Expect(isObjectReady(object(1, nil))).To(BeTrue())
+	})
+
+	It("considers objects with a true Ready condition ready", func() {
+		Expect(isObjectReady(object(1, map[string]any{
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		}))).To(BeTrue())
+	})
+
+	It("does not consider objects with a false Ready condition ready", func() {
+		Expect(isObjectReady(object(1, map[string]any{
+			"conditions": []any{map[string]any{"type": "Ready", "status": "False"}},
+		}))).To(BeFalse())
+	})
+
+	It("does not consider objects with an outdated status ready", func() {
+		Expect(isObjectReady(object(2, map[string]any{
+			"observedGeneration": int64(1),
+			"conditions":         []any{map[string]any{"type": "Ready", "status": "True"}},
+		}))).To(BeFalse())
+	})
+
+	It("considers resource graph definitions ready once kro accepted them", func() {
+		rgd := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "ResourceGraphDefinition",
+		}}
+		rgd.SetGeneration(2)
+		Expect(isObjectReady(rgd)).To(BeFalse())
+
+		rgd.Object["status"] = map[string]any{"conditions": []any{
+			map[string]any{"type": "GraphVerified", "status": "True", "observedGeneration": int64(2)},
+			map[string]any{"type": "CustomResourceDefinitionSynced", "status": "True", "observedGeneration": int64(2)},
+			map[string]any{"type": "ReconcilerReady", "status": "True", "observedGeneration": int64(2)},
+		}}
+		Expect(isObjectReady(rgd)).To(BeTrue())
+	})
+
+	It("does not consider resource graph definitions with conditions of an older generation ready", func() {
+		rgd := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "ResourceGraphDefinition",
+			"status": map[string]any{"conditions": []any{
+				map[string]any{"type": "GraphVerified", "status": "True", "observedGeneration": int64(1)},
+				map[string]any{"type": "CustomResourceDefinitionSynced", "status": "True", "observedGeneration": int64(1)},
+				map[string]any{"type": "ReconcilerReady", "status": "True", "observedGeneration": int64(1)},
+			}},
+		}}
+		rgd.SetGeneration(2)
+		Expect(isObjectReady(rgd)).To(BeFalse())
+	})
+})
@@ -0,0 +1,94 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// kubeConfigKeys are the keys of the kubeconfig secret that are tried if the deployer does not specify a key.
+var kubeConfigKeys = []string{"value", "value.yaml"}
+
+// kubeConfig returns the rest config of the remote cluster from the kubeconfig secret of the deployer. The secret is
+// read in the cluster of the controller.
+func (r *Reconciler) kubeConfig(ctx context.Context, deployer *deliveryv1alpha1.Deployer) (*rest.Config, error) {
+	ref := deployer.Spec.KubeConfig.SecretRef
+	namespace, err := resourceNamespace(deployer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine namespace of the kubeconfig secret: %w", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	keys := kubeConfigKeys
+	if ref.Key != "" {
+		keys = []string{ref.Key}
+	}
+
+	for _, key := range keys {
+		data, ok := secret.Data[key]
+		if !ok {
+			continue
+		}
+
+		cfg, err := restConfigFromKubeConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig in key %s of secret %s/%s: %w", key, namespace, ref.Name, err)
+		}
+
+		return cfg, nil
+	}
+
+	return nil, fmt.Errorf("secret %s/%s contains no kubeconfig in key %s", namespace, ref.Name, strings.Join(keys, " or "))
+}
+
+// restConfigFromKubeConfig returns the rest config of the current context of the kubeconfig. Kubeconfigs that execute
+// commands, use auth provider plugins, or reference files are rejected, as they would run in or read from the
+// filesystem of the controller.
+func restConfigFromKubeConfig(data []byte) (*rest.Config, error) {
+	kubeConfig, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, authInfo := range kubeConfig.AuthInfos {
+		if authInfo.Exec != nil || authInfo.AuthProvider != nil {
+			return nil, fmt.Errorf("user %s must not use exec or auth provider plugins", name)
+		}
+
+		if authInfo.TokenFile != "" || authInfo.ClientCertificate != "" || authInfo.ClientKey != "" {
+			return nil, fmt.Errorf("user %s must not reference files, embed the token, client-certificate-data, "+
+				"and client-key-data instead", name)
+		}
+	}
+
+	for name, cluster := range kubeConfig.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return nil, fmt.Errorf("cluster %s must not reference files, embed the certificate-authority-data "+
+				"instead", name)
+		}
+	}
+
+	cfg, err := clientcmd.NewDefaultClientConfig(*kubeConfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Host == "" {
+		return nil, errors.New("no server specified")
+	}
+
+	return cfg, nil
+}
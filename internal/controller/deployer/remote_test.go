@@ -0,0 +1,54 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("restConfigFromKubeConfig", func() {
+	kubeConfig := func(authInfo *clientcmdapi.AuthInfo) []byte {
+		config := clientcmdapi.NewConfig()
+		config.Clusters["remote"] = &clientcmdapi.Cluster{Server: "https://remote.example.com"}
+		config.AuthInfos["remote"] = authInfo
+		config.Contexts["remote"] = &clientcmdapi.Context{Cluster: "remote", AuthInfo: "remote"}
+		config.CurrentContext = "remote"
+
+		data, err := clientcmd.Write(*config)
+		Expect(err).NotTo(HaveOccurred())
+
+		return data
+	}
+
+	It("returns the config of the current context", func() {
+		cfg, err := restConfigFromKubeConfig(kubeConfig(&clientcmdapi.AuthInfo{Token: "token"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Host).To(Equal("https://remote.example.com"))
+		Expect(cfg.BearerToken).To(Equal("token"))
+	})
+
+	It("rejects exec plugins", func() {
+		_, err := restConfigFromKubeConfig(kubeConfig(&clientcmdapi.AuthInfo{
+			Exec: &clientcmdapi.ExecConfig{Command: "credential-helper"},
+		}))
+		Expect(err).To(MatchError(ContainSubstring("exec")))
+	})
+
+	It("rejects references to files", func() {
+		_, err := restConfigFromKubeConfig(kubeConfig(&clientcmdapi.AuthInfo{TokenFile: "/var/run/secrets/token"}))
+		Expect(err).To(MatchError(ContainSubstring("must not reference files")))
+
+		_, err = restConfigFromKubeConfig(kubeConfig(&clientcmdapi.AuthInfo{
+			ClientCertificate: "/etc/tls/tls.crt",
+			ClientKey:         "/etc/tls/tls.key",
+		}))
+		Expect(err).To(MatchError(ContainSubstring("must not reference files")))
+	})
+
+	It("rejects invalid kubeconfigs", func() {
+		_, err := restConfigFromKubeConfig([]byte("not a kubeconfig"))
+		Expect(err).To(HaveOccurred())
+	})
+})
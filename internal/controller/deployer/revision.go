@@ -0,0 +1,167 @@
+package deployer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"ocm.software/ocm/api/ocm/compdesc"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ocmctx "ocm.software/ocm/api/ocm"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/util"
+)
+
+// resourceRevision is the revision of a resource of the deployer that is deployed by the current reconciliation.
+type resourceRevision struct {
+	deliveryv1alpha1.DeployerResource
+
+	// data is the resource data.
+	data []byte
+	// manifest is the manifest that was decoded into the objects before it was decrypted. For Kustomize deployers, it
+	// is the kustomization before it was built, as its documents are decrypted before the build.
+	manifest []byte
+	// digest is the digest of the resource data.
+	digest string
+	// version is the version of the OCM resource.
+	version string
+	// componentVersion is the version of the component the resource belongs to.
+	componentVersion string
+	// objects are the objects decoded from the resource data.
+	objects []*unstructured.Unstructured
+}
+
+// fetchResource downloads the data of the referenced resource and verifies it against the digest of the resource. It
+// returns a util.NotReadyError or util.DeletionError if the resource is not available.
+func (r *Reconciler) fetchResource(
+	ctx context.Context,
+	octx ocmctx.Context,
+	session ocmctx.Session,
+	deployer *deliveryv1alpha1.Deployer,
+	ref deliveryv1alpha1.DeployerResource,
+) (*resourceRevision, error) {
+	resourceNamespace := ref.ResourceRef.Namespace
+	if resourceNamespace == "" {
+		resourceNamespace = deployer.GetNamespace()
+	}
+
+	resource, err := util.GetReadyObject[deliveryv1alpha1.Resource, *deliveryv1alpha1.Resource](ctx, r.Client, client.ObjectKey{
+		Namespace: resourceNamespace,
+		Name:      ref.ResourceRef.Name,
+	})
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.ResourceIsNotAvailable, err.Error())
+
+		return nil, fmt.Errorf("failed to get ready resource: %w", err)
+	}
+
+	// Download the resource
+	spec, err := octx.RepositorySpecForConfig(resource.Status.Component.RepositorySpec.Raw, nil)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return nil, fmt.Errorf("failed to get repository spec: %w", err)
+	}
+
+	repo, err := session.LookupRepository(octx, spec)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return nil, fmt.Errorf("invalid repository spec: %w", err)
+	}
+
+	cv, err := session.LookupComponentVersion(repo, resource.Status.Component.Component, resource.Status.Component.Version)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return nil, fmt.Errorf("failed to get component version: %w", err)
+	}
+
+	a := cv.GetDescriptor()
+	resourceReference, err := ocm.ResourceReferenceForID(a, resource.Spec.Resource)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, err.Error())
+
+		return nil, fmt.Errorf("failed to resolve resource: %w", err)
+	}
+
+	resourceAccess, _, err := ocm.GetResourceAccessForComponentVersion(
+		ctx,
+		cv,
+		resourceReference,
+		&ocm.Descriptors{List: []*compdesc.ComponentDescriptor{a}},
+		resource.Spec.SkipVerify,
+	)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, err.Error())
+
+		return nil, fmt.Errorf("failed to get resource access: %w", err)
+	}
+
+	// Get the resource data and its digest. Compare the digest to the one in the resource to make sure the resource is
+	// up to date.
+	data, digest, err := getResource(cv, resourceAccess)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, err.Error())
+
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	}
+
+	if resource.Status.Resource.Digest != digest {
+		status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.GetOCMResourceFailedReason, "resource digest mismatch")
+
+		return nil, fmt.Errorf("resource digest mismatch: expected %s, got %s", resource.Status.Resource.Digest, digest)
+	}
+
+	return &resourceRevision{
+		DeployerResource: ref,
+		data:             data,
+		digest:           digest,
+		version:          resourceAccess.Meta().GetVersion(),
+		componentVersion: resource.Status.Component.Version,
+	}, nil
+}
+
+// revisionDigest returns the digest identifying the revisions of all resources. The digest of a single resource is
+// used as is to identify it in the status.
+func revisionDigest(revisions []*resourceRevision) string {
+	if len(revisions) == 1 {
+		return revisions[0].digest
+	}
+
+	digests := make([]string, 0, len(revisions))
+	for _, revision := range revisions {
+		digests = append(digests, revision.digest)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(digests, "\n")))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// revisionVersion returns the versions of all resources for messages.
+func revisionVersion(revisions []*resourceRevision) string {
+	versions := make([]string, 0, len(revisions))
+	for _, revision := range revisions {
+		versions = append(versions, revision.version)
+	}
+
+	return strings.Join(versions, ", ")
+}
+
+// resourceStatus returns the initial status of the resource revision before it is deployed.
+func resourceStatus(revision *resourceRevision) deliveryv1alpha1.DeployedResourceStatus {
+	return deliveryv1alpha1.DeployedResourceStatus{
+		ResourceRef: revision.ResourceRef,
+		Version:     revision.version,
+		Digest:      revision.digest,
+		Message:     "Waiting for the previous resources to be deployed",
+	}
+}
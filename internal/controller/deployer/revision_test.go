@@ -0,0 +1,22 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("revisionDigest", func() {
+	first := &resourceRevision{digest: "sha256:first"}
+	second := &resourceRevision{digest: "sha256:second"}
+
+	It("returns the digest of a single resource", func() {
+		Expect(revisionDigest([]*resourceRevision{first})).To(Equal("sha256:first"))
+	})
+
+	It("returns a digest depending on the order of the resources", func() {
+		digest := revisionDigest([]*resourceRevision{first, second})
+		Expect(digest).To(HavePrefix("sha256:"))
+		Expect(digest).To(Equal(revisionDigest([]*resourceRevision{first, second})))
+		Expect(digest).NotTo(Equal(revisionDigest([]*resourceRevision{second, first})))
+	})
+})
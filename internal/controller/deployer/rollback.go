@@ -0,0 +1,270 @@
+package deployer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
+)
+
+// healthyRevision returns the revision of the resources that can be restored if a later revision fails.
+func healthyRevision(digest string, revisions []*resourceRevision) (*deliveryv1alpha1.DeployedRevision, error) {
+	healthy := &deliveryv1alpha1.DeployedRevision{
+		Digest:    digest,
+		Resources: make([]deliveryv1alpha1.DeployedResourceRevision, 0, len(revisions)),
+	}
+
+	for _, revision := range revisions {
+		manifest, err := compressManifest(revision.manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress manifest of resource %s: %w", revision.ResourceRef.Name, err)
+		}
+
+		healthy.Resources = append(healthy.Resources, deliveryv1alpha1.DeployedResourceRevision{
+			ResourceRef:      revision.ResourceRef,
+			ComponentVersion: revision.componentVersion,
+			Version:          revision.version,
+			Digest:           revision.digest,
+			Manifest:         manifest,
+		})
+	}
+
+	return healthy, nil
+}
+
+// healthyVersion returns the versions of all resources of the healthy revision for messages.
+func healthyVersion(healthy *deliveryv1alpha1.DeployedRevision) string {
+	versions := make([]string, 0, len(healthy.Resources))
+	for _, resource := range healthy.Resources {
+		versions = append(versions, resource.Version)
+	}
+
+	return strings.Join(versions, ", ")
+}
+
+// rollback re-applies the last healthy revision of the deployer after the revision with the given digest and version
+// failed with the given cause. It returns false if rollbacks are disabled or no other healthy revision is known, so
+// that the failure must be handled by the caller.
+func (r *Reconciler) rollback(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	digest, version string,
+	cause error,
+) (bool, error) {
+	healthy := deployer.Status.LastHealthyRevision
+	if !deployer.Spec.RollbackOnFailure || healthy == nil || healthy.Digest == digest {
+		return false, nil
+	}
+
+	logger := log.FromContext(ctx)
+	healthyVersion := healthyVersion(healthy)
+	logger.Info("rolling back failed revision", "version", version, "healthyVersion", healthyVersion, "cause", cause.Error())
+
+	fail := func(err error) (bool, error) {
+		status.MarkNotReady(r.EventRecorder, deployer, failureReason(err, deliveryv1alpha1.RollbackFailedReason),
+			fmt.Sprintf("version %s failed: %s; rollback to version %s failed: %s", version, cause, healthyVersion, err))
+
+		return false, fmt.Errorf("failed to roll back to version %s: %w", healthyVersion, err)
+	}
+
+	var decryption *decryptionKeys
+	if deployer.Spec.Decryption != nil {
+		var err error
+		if decryption, err = r.decryptionKeys(ctx, deployer); err != nil {
+			return fail(err)
+		}
+		defer decryption.cleanup()
+	}
+
+	var applied []*unstructured.Unstructured
+	resources := make([]deliveryv1alpha1.DeployedResourceStatus, 0, len(healthy.Resources))
+	rendered := make([]*resourceRevision, 0, len(healthy.Resources))
+	for _, resource := range healthy.Resources {
+		manifest, err := decompressManifest(resource.Manifest)
+		if err != nil {
+			return fail(fmt.Errorf("failed to decompress manifest of resource %s: %w", resource.ResourceRef.Name, err))
+		}
+
+		// The manifests are stored before they are built, decrypted, and post-rendered
+		rendered = append(rendered, &resourceRevision{manifest: manifest})
+		switch {
+		case deployer.Spec.Type == deliveryv1alpha1.DeployerTypeKustomize:
+			kustomize := ptr.Deref(deployer.Spec.Kustomize, deliveryv1alpha1.KustomizeSpec{})
+			if manifest, err = buildKustomization(manifest, kustomize, decryption); err != nil {
+				return fail(fmt.Errorf("failed to build kustomization of resource %s: %w", resource.ResourceRef.Name, err))
+			}
+		case decryption != nil:
+			if manifest, err = decryptManifest(manifest, decryption); err != nil {
+				return fail(fmt.Errorf("failed to decrypt manifest of resource %s: %w", resource.ResourceRef.Name, err))
+			}
+		}
+
+		objs, err := decodeObjects(deployer.Spec.Type, manifest)
+		if err != nil {
+			return fail(fmt.Errorf("failed to unmarshal manifest of resource %s: %w", resource.ResourceRef.Name, err))
+		}
+
+		if deployer.Spec.PostRender != nil {
+			if objs, err = postRender(objs, *deployer.Spec.PostRender); err != nil {
+				return fail(fmt.Errorf("failed to post-render objects of resource %s: %w", resource.ResourceRef.Name, err))
+			}
+		}
+		unmarkDecrypted(objs)
+
+		for _, obj := range objs {
+			actual, err := r.applyObject(ctx, clnt, deployer, obj, deployer.Spec.Force)
+			if err != nil {
+				return fail(fmt.Errorf("failed to apply %s: %w", objectRef(obj), err))
+			}
+
+			applied = append(applied, actual)
+		}
+
+		resources = append(resources, deliveryv1alpha1.DeployedResourceStatus{
+			ResourceRef: resource.ResourceRef,
+			Version:     resource.Version,
+			Digest:      resource.Digest,
+			Ready:       true,
+			Message:     fmt.Sprintf("Rolled back to version %s", resource.Version),
+		})
+	}
+
+	// Delete objects that were applied by the failed revision but are not part of the healthy one
+	inventory := newInventory(applied)
+	if entry, ok := instanceEntry(deployer); ok {
+		// Keep the instance, its resource graph definition is part of the healthy revision as well
+		inventory = append(inventory, entry)
+	}
+	if ptr.Deref(deployer.Spec.Prune, true) {
+		if err := r.prune(ctx, clnt, deployer, staleEntries(deployer.Status.Inventory, inventory)); err != nil {
+			return fail(fmt.Errorf("failed to prune objects: %w", err))
+		}
+	}
+
+	patchedDigest, err := postRenderDigest(deployer, rendered)
+	if err != nil {
+		return fail(err)
+	}
+
+	deployer.Status.Inventory = inventory
+	deployer.Status.LastAppliedDigest = healthy.Digest
+	deployer.Status.LastAppliedPostRenderDigest = patchedDigest
+	deployer.Status.LastAppliedTime = &metav1.Time{Time: time.Now()}
+	deployer.Status.Resources = resources
+	deployer.Status.FailedRevision = &deliveryv1alpha1.FailedRevision{
+		Version:            version,
+		Digest:             digest,
+		ObservedGeneration: deployer.GetGeneration(),
+	}
+
+	status.MarkNotReady(r.EventRecorder, deployer, deliveryv1alpha1.RolledBackReason,
+		fmt.Sprintf("version %s failed: %s; rolled back to version %s", version, cause, healthyVersion))
+
+	return true, nil
+}
+
+// isRolledBack returns true if the revision with the given digest was rolled back and must not be retried until the
+// resources or the spec of the deployer change. The restored last healthy revision is reconciled instead.
+func isRolledBack(deployer *deliveryv1alpha1.Deployer, digest string) bool {
+	failed := deployer.Status.FailedRevision
+
+	return deployer.Spec.RollbackOnFailure && failed != nil && deployer.Status.LastHealthyRevision != nil &&
+		failed.Digest == digest && failed.ObservedGeneration == deployer.GetGeneration()
+}
+
+// healthyRevisions returns the revisions of the resources of the last healthy revision of the deployer, so that the
+// restored revision is reconciled like the revisions of fetched resources.
+func healthyRevisions(deployer *deliveryv1alpha1.Deployer) ([]*resourceRevision, error) {
+	waitForReady := map[deliveryv1alpha1.ObjectKey]bool{}
+	for _, resource := range deployer.GetResources() {
+		waitForReady[resource.ResourceRef] = resource.WaitForReady
+	}
+
+	healthy := deployer.Status.LastHealthyRevision
+	revisions := make([]*resourceRevision, 0, len(healthy.Resources))
+	for _, resource := range healthy.Resources {
+		data, err := decompressManifest(resource.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress manifest of resource %s: %w", resource.ResourceRef.Name, err)
+		}
+
+		revisions = append(revisions, &resourceRevision{
+			DeployerResource: deliveryv1alpha1.DeployerResource{
+				ResourceRef:  resource.ResourceRef,
+				WaitForReady: waitForReady[resource.ResourceRef],
+			},
+			data:             data,
+			digest:           resource.Digest,
+			version:          resource.Version,
+			componentVersion: resource.ComponentVersion,
+		})
+	}
+
+	return revisions, nil
+}
+
+// rollbackResult returns the result of a reconciliation that rolled back a failed revision. The restored revision is
+// reconciled again at the interval of the deployer, so that its drift and health are still checked.
+func rollbackResult(deployer *deliveryv1alpha1.Deployer, err error) (ctrl.Result, error) {
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: deployer.GetRequeueAfter()}, nil
+}
+
+// markRolledBack keeps reporting the rollback of the failed revision after the restored revision was reconciled. The
+// message of the rollback is kept, as it contains the cause of the failure. No event is recorded, as the rollback was
+// already reported.
+func markRolledBack(deployer *deliveryv1alpha1.Deployer, version string) {
+	msg := conditions.GetMessage(deployer, meta.ReadyCondition)
+	if conditions.GetReason(deployer, meta.ReadyCondition) != deliveryv1alpha1.RolledBackReason {
+		msg = fmt.Sprintf("version %s failed; rolled back to version %s", deployer.Status.FailedRevision.Version, version)
+	}
+
+	conditions.Delete(deployer, meta.ReconcilingCondition)
+	conditions.MarkFalse(deployer, meta.ReadyCondition, deliveryv1alpha1.RolledBackReason, "%s", msg)
+}
+
+// compressManifest compresses the manifest to keep the size of the status of the deployer small.
+func compressManifest(manifest []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressManifest decompresses a manifest compressed by compressManifest.
+func decompressManifest(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
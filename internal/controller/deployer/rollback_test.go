@@ -0,0 +1,114 @@
+package deployer
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+var _ = Describe("healthyRevision", func() {
+	It("keeps the compressed manifest of every resource", func() {
+		revision := &resourceRevision{
+			DeployerResource: deliveryv1alpha1.DeployerResource{ResourceRef: deliveryv1alpha1.ObjectKey{Name: "manifest"}},
+			manifest:         []byte("apiVersion: v1\nkind: ConfigMap\n"),
+			digest:           "sha256:first",
+			version:          "1.0.0",
+			componentVersion: "2.0.0",
+		}
+
+		healthy, err := healthyRevision("sha256:first", []*resourceRevision{revision})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(healthy.Digest).To(Equal("sha256:first"))
+		Expect(healthy.Resources).To(HaveLen(1))
+		Expect(healthy.Resources[0].ComponentVersion).To(Equal("2.0.0"))
+		Expect(healthyVersion(healthy)).To(Equal("1.0.0"))
+
+		manifest, err := decompressManifest(healthy.Resources[0].Manifest)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifest).To(Equal(revision.manifest))
+	})
+})
+
+var _ = Describe("healthyRevisions", func() {
+	It("restores the revisions of the last healthy revision", func() {
+		revision := &resourceRevision{
+			DeployerResource: deliveryv1alpha1.DeployerResource{ResourceRef: deliveryv1alpha1.ObjectKey{Name: "manifest"}},
+			manifest:         []byte("apiVersion: v1\nkind: ConfigMap\n"),
+			digest:           "sha256:first",
+			version:          "1.0.0",
+			componentVersion: "2.0.0",
+		}
+
+		healthy, err := healthyRevision("sha256:first", []*resourceRevision{revision})
+		Expect(err).NotTo(HaveOccurred())
+
+		deployer := &deliveryv1alpha1.Deployer{
+			Spec: deliveryv1alpha1.DeployerSpec{Resources: []deliveryv1alpha1.DeployerResource{{
+				ResourceRef:  deliveryv1alpha1.ObjectKey{Name: "manifest"},
+				WaitForReady: true,
+			}}},
+			Status: deliveryv1alpha1.DeployerStatus{LastHealthyRevision: healthy},
+		}
+
+		revisions, err := healthyRevisions(deployer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(revisions).To(HaveLen(1))
+		Expect(revisions[0].data).To(Equal(revision.manifest))
+		Expect(revisions[0].WaitForReady).To(BeTrue())
+		Expect(revisions[0].digest).To(Equal(revision.digest))
+		Expect(revisions[0].version).To(Equal(revision.version))
+		Expect(revisions[0].componentVersion).To(Equal(revision.componentVersion))
+		Expect(revisionVersion(revisions)).To(Equal(healthyVersion(healthy)))
+	})
+})
+
+var _ = Describe("rollbackResult", func() {
+	It("reconciles the restored revision again at the interval of the deployer", func() {
+		deployer := &deliveryv1alpha1.Deployer{
+			Spec: deliveryv1alpha1.DeployerSpec{Interval: metav1.Duration{Duration: time.Minute}},
+		}
+
+		result, err := rollbackResult(deployer, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		result, err = rollbackResult(deployer, errors.New("failed"))
+		Expect(err).To(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+	})
+})
+
+var _ = Describe("isRolledBack", func() {
+	deployer := func(rollbackOnFailure bool, generation int64) *deliveryv1alpha1.Deployer {
+		return &deliveryv1alpha1.Deployer{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Spec:       deliveryv1alpha1.DeployerSpec{RollbackOnFailure: rollbackOnFailure},
+			Status: deliveryv1alpha1.DeployerStatus{
+				FailedRevision:      &deliveryv1alpha1.FailedRevision{Digest: "sha256:failed", ObservedGeneration: 1},
+				LastHealthyRevision: &deliveryv1alpha1.DeployedRevision{Digest: "sha256:healthy"},
+			},
+		}
+	}
+
+	It("skips the failed revision", func() {
+		Expect(isRolledBack(deployer(true, 1), "sha256:failed")).To(BeTrue())
+	})
+
+	It("retries the failed revision if the spec changed", func() {
+		Expect(isRolledBack(deployer(true, 2), "sha256:failed")).To(BeFalse())
+	})
+
+	It("deploys other revisions", func() {
+		Expect(isRolledBack(deployer(true, 1), "sha256:other")).To(BeFalse())
+	})
+
+	It("retries the failed revision if rollbacks are disabled", func() {
+		Expect(isRolledBack(deployer(false, 1), "sha256:failed")).To(BeFalse())
+	})
+})
@@ -131,6 +131,7 @@ var _ = BeforeSuite(func() {
 			Scheme:        testEnv.Scheme,
 			EventRecorder: recorder,
 		},
+		RESTConfig: cfg,
 	}).SetupWithManager(ctx, k8sManager)).To(Succeed())
 
 	go func() {
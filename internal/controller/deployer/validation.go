@@ -0,0 +1,119 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kro-run/kro/pkg/simpleschema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	krov1alpha1 "github.com/kro-run/kro/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// validateObjects validates all objects before any of them is applied and returns the violations with their field
+// paths. The objects are dry-run applied with strict field validation to check them against the OpenAPI schema of
+// the cluster. The schema of resource graph definitions is additionally checked to be a valid kro simple schema.
+// Objects whose kind or namespace does not exist yet, e.g. because they are deployed by the same revision, are
+// validated when they are applied.
+func (r *Reconciler) validateObjects(
+	ctx context.Context,
+	clnt client.Client,
+	deployer *deliveryv1alpha1.Deployer,
+	objs []*unstructured.Unstructured,
+) ([]string, error) {
+	var violations []string
+	for _, obj := range objs {
+		if obj.GroupVersionKind().GroupKind() == rgdGroupKind {
+			violations = append(violations, validateResourceGraphDefinition(obj)...)
+		}
+
+		_, err := r.applyObject(ctx, clnt, deployer, obj, deployer.Spec.Force,
+			client.DryRunAll, client.FieldValidation(metav1.FieldValidationStrict))
+		switch {
+		case err == nil:
+		case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+			violations = append(violations, statusViolations(obj, err)...)
+		case apimeta.IsNoMatchError(err) || apierrors.IsNotFound(err) ||
+			apierrors.IsConflict(err) || apierrors.IsForbidden(err):
+			// Missing kinds and namespaces, conflicts and missing permissions are reported when applying the object
+		default:
+			return nil, fmt.Errorf("failed to validate %s: %w", objectRef(obj), err)
+		}
+	}
+
+	return violations, nil
+}
+
+// statusViolations returns the causes of the validation error of the API server with their field paths.
+func statusViolations(obj *unstructured.Unstructured, err error) []string {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) || apiStatus.Status().Details == nil || len(apiStatus.Status().Details.Causes) == 0 {
+		return []string{fmt.Sprintf("%s: %s", objectRef(obj), err)}
+	}
+
+	causes := apiStatus.Status().Details.Causes
+	violations := make([]string, 0, len(causes))
+	for _, cause := range causes {
+		if cause.Field == "" {
+			violations = append(violations, fmt.Sprintf("%s: %s", objectRef(obj), cause.Message))
+
+			continue
+		}
+
+		violations = append(violations, fmt.Sprintf("%s: %s: %s", objectRef(obj), cause.Field, cause.Message))
+	}
+
+	return violations
+}
+
+// validateResourceGraphDefinition checks that the schema of the resource graph definition is a valid kro simple
+// schema.
+func validateResourceGraphDefinition(obj *unstructured.Unstructured) []string {
+	rgd := &krov1alpha1.ResourceGraphDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, rgd); err != nil {
+		return []string{fmt.Sprintf("%s: %s", objectRef(obj), err)}
+	}
+
+	if rgd.Spec.Schema == nil {
+		return []string{fmt.Sprintf("%s: spec.schema: Required value", objectRef(obj))}
+	}
+
+	spec, err := rawMap(rgd.Spec.Schema.Spec)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: spec.schema.spec: %s", objectRef(obj), err)}
+	}
+
+	types, err := rawMap(rgd.Spec.Schema.Types)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: spec.schema.types: %s", objectRef(obj), err)}
+	}
+
+	if _, err := simpleschema.ToOpenAPISpec(spec, types); err != nil {
+		return []string{fmt.Sprintf("%s: spec.schema.spec: %s", objectRef(obj), err)}
+	}
+
+	return nil
+}
+
+// rawMap unmarshals the raw extension into a map. An empty extension results in an empty map.
+func rawMap(raw runtime.RawExtension) (map[string]any, error) {
+	result := map[string]any{}
+	if len(raw.Raw) == 0 {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(raw.Raw, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
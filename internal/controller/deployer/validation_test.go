@@ -0,0 +1,72 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var _ = Describe("statusViolations", func() {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName("invalid")
+
+	It("returns every cause with its field path", func() {
+		err := apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "invalid", field.ErrorList{
+			field.Invalid(field.NewPath("metadata", "name"), "Invalid_Name", "must be lowercase"),
+			field.Required(field.NewPath("data"), ""),
+		})
+
+		violations := statusViolations(obj, err)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0]).To(ContainSubstring("metadata.name: Invalid value"))
+		Expect(violations[1]).To(ContainSubstring("data: Required value"))
+	})
+
+	It("returns the error without causes", func() {
+		violations := statusViolations(obj, apierrors.NewBadRequest(`strict decoding error: unknown field "spec.foo"`))
+		Expect(violations).To(ConsistOf(ContainSubstring(`unknown field "spec.foo"`)))
+	})
+})
+
+var _ = Describe("validateResourceGraphDefinition", func() {
+	rgd := func(schema map[string]any) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "kro.run/v1alpha1",
+			"kind":       "ResourceGraphDefinition",
+			"metadata":   map[string]any{"name": "rgd"},
+			"spec":       map[string]any{},
+		}}
+		if schema != nil {
+			Expect(unstructured.SetNestedMap(obj.Object, schema, "spec", "schema")).To(Succeed())
+		}
+
+		return obj
+	}
+
+	It("accepts a valid simple schema", func() {
+		Expect(validateResourceGraphDefinition(rgd(map[string]any{
+			"apiVersion": "v1alpha1",
+			"kind":       "WebApp",
+			"spec":       map[string]any{"name": "string", "replicas": "integer | default=1"},
+		}))).To(BeEmpty())
+	})
+
+	It("rejects an unknown type", func() {
+		Expect(validateResourceGraphDefinition(rgd(map[string]any{
+			"apiVersion": "v1alpha1",
+			"kind":       "WebApp",
+			"spec":       map[string]any{"name": "unknownType"},
+		}))).To(ConsistOf(ContainSubstring("spec.schema.spec")))
+	})
+
+	It("requires a schema", func() {
+		Expect(validateResourceGraphDefinition(rgd(nil))).To(ConsistOf(ContainSubstring("spec.schema: Required value")))
+	})
+})
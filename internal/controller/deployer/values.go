@@ -0,0 +1,148 @@
+package deployer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	deliveryv1alpha1 "github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// defaultValuesKey is the key of the values in a referenced config map or secret if no key is specified.
+const defaultValuesKey = "values.yaml"
+
+// getValues returns the values of the referenced config maps and secrets. Later values are merged on top of earlier
+// ones.
+func (r *Reconciler) getValues(
+	ctx context.Context,
+	deployer *deliveryv1alpha1.Deployer,
+	refs []deliveryv1alpha1.ValuesReference,
+) (map[string]any, error) {
+	values := map[string]any{}
+	for _, ref := range refs {
+		data, err := r.getValuesData(ctx, deployer, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		if data == nil {
+			continue
+		}
+
+		refValues := map[string]any{}
+		if err := yaml.Unmarshal(data, &refValues); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal values of %s %s: %w", ref.Kind, ref.Name, err)
+		}
+
+		values = mergeValues(values, refValues)
+	}
+
+	return values, nil
+}
+
+// getValuesData returns the raw values of the referenced config map or secret. It returns no data if an optional
+// reference cannot be resolved.
+func (r *Reconciler) getValuesData(
+	ctx context.Context,
+	deployer *deliveryv1alpha1.Deployer,
+	ref deliveryv1alpha1.ValuesReference,
+) ([]byte, error) {
+	key := client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		namespace, err := resourceNamespace(deployer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine namespace of %s %s: %w", ref.Kind, ref.Name, err)
+		}
+
+		key.Namespace = namespace
+	}
+
+	valuesKey := ref.ValuesKey
+	if valuesKey == "" {
+		valuesKey = defaultValuesKey
+	}
+
+	var (
+		data  []byte
+		found bool
+	)
+	switch ref.Kind {
+	case "ConfigMap":
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			if apierrors.IsNotFound(err) && ref.Optional {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("failed to get config map %s: %w", key, err)
+		}
+
+		var value string
+		value, found = configMap.Data[valuesKey]
+		data = []byte(value)
+	case "Secret":
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, key, secret); err != nil {
+			if apierrors.IsNotFound(err) && ref.Optional {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("failed to get secret %s: %w", key, err)
+		}
+
+		data, found = secret.Data[valuesKey]
+	default:
+		return nil, fmt.Errorf("unsupported kind %s of values reference %s", ref.Kind, ref.Name)
+	}
+
+	if !found {
+		if ref.Optional {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("key %s not found in %s %s", valuesKey, ref.Kind, key)
+	}
+
+	return data, nil
+}
+
+// mergeValues merges the override values recursively on top of the base values.
+func mergeValues(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base))
+	maps.Copy(merged, base)
+
+	for key, value := range override {
+		if overrideMap, ok := value.(map[string]any); ok {
+			if baseMap, ok := merged[key].(map[string]any); ok {
+				merged[key] = mergeValues(baseMap, overrideMap)
+
+				continue
+			}
+		}
+
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// digestValues returns a digest of the values to detect changes.
+func digestValues(values map[string]any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
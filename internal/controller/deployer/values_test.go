@@ -0,0 +1,25 @@
+package deployer
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mergeValues", func() {
+	It("merges maps recursively and replaces other values", func() {
+		base := map[string]any{
+			"image": map[string]any{"repository": "podinfo", "tag": "6.7.0"},
+			"ports": []any{8080},
+		}
+		override := map[string]any{
+			"image": map[string]any{"tag": "6.7.1"},
+			"ports": []any{9090},
+		}
+
+		Expect(mergeValues(base, override)).To(Equal(map[string]any{
+			"image": map[string]any{"repository": "podinfo", "tag": "6.7.1"},
+			"ports": []any{9090},
+		}))
+		Expect(base["image"]).To(HaveKeyWithValue("tag", "6.7.0"))
+	})
+})
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/fluxcd/pkg/runtime/patch"
@@ -12,12 +14,8 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"ocm.software/ocm/api/datacontext"
-	"ocm.software/ocm/api/oci"
-	"ocm.software/ocm/api/ocm/extensions/accessmethods/git"
-	"ocm.software/ocm/api/ocm/extensions/accessmethods/github"
-	"ocm.software/ocm/api/ocm/extensions/accessmethods/helm"
-	"ocm.software/ocm/api/ocm/extensions/accessmethods/ociartifact"
 	"ocm.software/ocm/api/ocm/resolvers"
+	"ocm.software/ocm/api/utils/blobaccess"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -26,20 +24,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	giturls "github.com/chainguard-dev/git-urls"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	ocmctx "ocm.software/ocm/api/ocm"
-	v1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/util"
+	"github.com/open-component-model/ocm-k8s-toolkit/pkg/sourceref"
 )
 
 type Reconciler struct {
 	*ocm.BaseReconciler
+
+	// Storage stores the verified blobs of the resources as artifacts. If not set, no artifacts are stored.
+	Storage *artifact.Storage
+
+	// Registry is the OCI registry the verified blobs of the resources that enable pushing are pushed to. The source
+	// reference of such a resource then points to the pushed artifact. If not set, no artifacts are pushed.
+	Registry *artifact.Registry
 }
 
 var _ ocm.Reconciler = (*Reconciler)(nil)
@@ -72,11 +77,17 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 				return nil
 			}
 
-			return []string{fmt.Sprintf(
-				"%s/%s",
-				deployer.Spec.ResourceRef.Namespace,
-				deployer.Spec.ResourceRef.Name,
-			)}
+			resources := deployer.GetResources()
+			keys := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				keys = append(keys, fmt.Sprintf(
+					"%s/%s",
+					resource.ResourceRef.Namespace,
+					resource.ResourceRef.Name,
+				))
+			}
+
+			return keys
 		},
 	); err != nil {
 		return fmt.Errorf("failed setting index fields: %w", err)
@@ -126,25 +137,30 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 					return []reconcile.Request{}
 				}
 
-				resource := &v1alpha1.Resource{}
-				if err := r.Get(ctx, client.ObjectKey{
-					Namespace: deployer.Spec.ResourceRef.Namespace,
-					Name:      deployer.Spec.ResourceRef.Name,
-				}, resource); err != nil {
-					return []reconcile.Request{}
-				}
+				var requests []reconcile.Request
+				for _, ref := range deployer.GetResources() {
+					resource := &v1alpha1.Resource{}
+					if err := r.Get(ctx, client.ObjectKey{
+						Namespace: ref.ResourceRef.Namespace,
+						Name:      ref.ResourceRef.Name,
+					}, resource); err != nil {
+						continue
+					}
+
+					// Only reconcile if the resource is marked for deletion
+					if resource.GetDeletionTimestamp().IsZero() {
+						continue
+					}
 
-				// Only reconcile if the resource is marked for deletion
-				if resource.GetDeletionTimestamp().IsZero() {
-					return []reconcile.Request{}
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Namespace: resource.GetNamespace(),
+							Name:      resource.GetName(),
+						},
+					})
 				}
 
-				return []reconcile.Request{
-					{NamespacedName: types.NamespacedName{
-						Namespace: resource.GetNamespace(),
-						Name:      resource.GetName(),
-					}},
-				}
+				return requests
 			})).
 		Complete(r)
 }
@@ -201,6 +217,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 			return ctrl.Result{}, errors.New(msg)
 		}
 
+		if r.Storage != nil {
+			if err := r.Storage.Remove(resource); err != nil {
+				status.MarkNotReady(r.EventRecorder, resource, v1alpha1.DeletionFailedReason, err.Error())
+
+				return ctrl.Result{}, err
+			}
+		}
+
+		if r.Registry != nil {
+			if err := r.Registry.Delete(ctx, resource, resource.Status.Reference); err != nil {
+				status.MarkNotReady(r.EventRecorder, resource, v1alpha1.DeletionFailedReason, err.Error())
+
+				return ctrl.Result{}, err
+			}
+		}
+
 		if updated := controllerutil.RemoveFinalizer(resource, v1alpha1.ResourceFinalizer); updated {
 			if err := r.Update(ctx, resource); err != nil {
 				status.MarkNotReady(r.EventRecorder, resource, v1alpha1.DeletionFailedReason, err.Error())
@@ -308,9 +340,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, fmt.Errorf("failed to list verified descriptors: %w", err)
 	}
 
-	resourceReference := v1.ResourceReference{
-		Resource:      resource.Spec.Resource.ByReference.Resource,
-		ReferencePath: resource.Spec.Resource.ByReference.ReferencePath,
+	resourceReference, err := ocm.ResourceReferenceForID(cv.GetDescriptor(), resource.Spec.Resource)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, resource, v1alpha1.GetOCMResourceFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to resolve resource: %w", err)
 	}
 
 	resourceAccess, resourceCompDesc, err := ocm.GetResourceAccessForComponentVersion(
@@ -335,13 +369,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	// TODO: Must be adjusted when Kro supports CEL optionals (@frewilhelm)
 	//   (see https://github.com/open-component-model/ocm-project/issues/455)
-	sourceRef, err := getSourceRefForAccessSpec(ctx, accSpec, cv)
+	sourceRef, err := sourceref.ForAccessSpec(ctx, accSpec, cv)
 	if err != nil {
 		status.MarkNotReady(r.EventRecorder, resource, v1alpha1.GetReferenceFailedReason, err.Error())
 
 		return ctrl.Result{}, fmt.Errorf("failed to get source reference: %w", err)
 	}
 
+	// The blob is only downloaded if it is stored or pushed, and removed at the end of the reconciliation
+	blob := &verifiedBlob{access: resourceAccess, cv: cv, skipVerify: resource.Spec.SkipVerify}
+	defer blob.Remove()
+	unchanged := blobUnchanged(resource, resourceAccess)
+
+	// The blob is served from the registry instead of its source, if the resource enables pushing
+	if r.Registry != nil {
+		if resource.Spec.PushArtifact {
+			if sourceRef, err = r.pushArtifact(ctx, resource, blob, unchanged); err != nil {
+				status.MarkNotReady(r.EventRecorder, resource, v1alpha1.StorageOperationFailedReason, err.Error())
+
+				return ctrl.Result{}, fmt.Errorf("failed to push artifact: %w", err)
+			}
+		}
+
+		// The previously pushed artifact is deleted before the status is updated, so that a failed deletion is retried
+		if pushed := resource.Status.Reference; pushed != nil && (sourceRef == nil || pushed.Digest != sourceRef.Digest) {
+			if err := r.Registry.Delete(ctx, resource, pushed); err != nil {
+				status.MarkNotReady(r.EventRecorder, resource, v1alpha1.StorageOperationFailedReason, err.Error())
+
+				return ctrl.Result{}, fmt.Errorf("failed to delete stale artifact: %w", err)
+			}
+		}
+	}
+
 	// Get repository spec of actual component descriptor of the referenced resource
 	resolver := resolvers.NewCompoundResolver(repo, octx.GetResolver())
 	resCompVers, err := session.LookupComponentVersion(resolver, resourceCompDesc.GetName(), resourceCompDesc.GetVersion())
@@ -370,113 +429,169 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, fmt.Errorf("failed to set resource status: %w", err)
 	}
 
+	if r.Storage != nil {
+		if err := r.storeArtifact(resource, blob, unchanged); err != nil {
+			status.MarkNotReady(r.EventRecorder, resource, v1alpha1.StorageOperationFailedReason, err.Error())
+
+			return ctrl.Result{}, fmt.Errorf("failed to store artifact: %w", err)
+		}
+	}
+
 	status.MarkReady(r.EventRecorder, resource, "Applied version %s", resourceAccess.Meta().GetVersion())
 
 	return ctrl.Result{RequeueAfter: resource.GetRequeueAfter()}, nil
 }
 
-// getSourceRefForAccessSpec determines the source reference for a given access specification.
-// It supports multiple access types (e.g., OCI, Helm, GitHub, Git) and extracts relevant
-// information such as registry, repository, and reference details.
-func getSourceRefForAccessSpec(ctx context.Context, accSpec any, cv ocmctx.ComponentVersionAccess) (*v1alpha1.SourceReference, error) {
-	logger := log.FromContext(ctx)
+// storeArtifact stores the verified blob of the resource as artifact and deletes the stale artifacts of the resource. If
+// the blob did not change and the file of the artifact still exists, it is not stored again.
+func (r *Reconciler) storeArtifact(resource *v1alpha1.Resource, blob *verifiedBlob, unchanged bool) error {
+	meta := blob.access.Meta()
+	if current := resource.Status.Artifact; unchanged && current != nil && current.Revision == meta.GetVersion() &&
+		r.Storage.Exists(resource, current) {
+		return r.Storage.GarbageCollect(resource, current)
+	}
 
-	switch access := accSpec.(type) {
-	case *ociartifact.AccessSpec:
-		ociURLDigest, err := access.GetOCIReference(cv)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get OCI reference: %w", err)
-		}
+	reader, mediaType, err := blob.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
 
-		// TODO: Replace with another reference parser that is not ocm v1 lib (@frewilhelm)
-		//   Why is it needed in the first place?
-		//   Because if a reference consists of a tag and a digest, we need to store both of them.
-		//   Additionally, consuming resources, as a HelmRelease or OCIRepository, might need the tag, the digest, or
-		//   both of them. Thus, we have to offer some flexibility here.
-		//   ocm v2 lib offers a LooseReference that is able to parse a reference with a tag and a digest. However, the
-		//   functionality is placed in an internal package and not available for us (yet).
-		ref, err := oci.ParseRef(ociURLDigest)
+	stored, err := r.Storage.Store(resource, meta.GetVersion(), meta.GetName(), mediaType, reader)
+	if err != nil {
+		return err
+	}
+
+	// The artifact is only updated if its content changed
+	if current := resource.Status.Artifact; current != nil && current.Digest == stored.Digest {
+		stored.LastUpdateTime = current.LastUpdateTime
+	}
+	resource.Status.Artifact = stored
+
+	return r.Storage.GarbageCollect(resource, stored)
+}
+
+// pushArtifact pushes the verified blob of the resource as single layer OCI artifact to the registry and returns the
+// reference of the pushed artifact. If the blob did not change and the pushed artifact still exists in the registry, it
+// is not pushed again.
+func (r *Reconciler) pushArtifact(
+	ctx context.Context,
+	resource *v1alpha1.Resource,
+	blob *verifiedBlob,
+	unchanged bool,
+) (*v1alpha1.SourceReference, error) {
+	meta := blob.access.Meta()
+	if pushed := resource.Status.Reference; unchanged && pushed != nil {
+		exists, err := r.Registry.Exists(ctx, resource, meta.GetVersion(), pushed)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse OCI reference: %w", err)
+			return nil, err
 		}
 
-		var tag string
-		if ref.Tag != nil {
-			tag = *ref.Tag
+		if exists {
+			return pushed, nil
 		}
+	}
 
-		var digest string
-		if ref.Digest != nil {
-			digest = ref.Digest.String()
-		}
+	reader, mediaType, err := blob.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
 
-		var reference string
-		if tag != "" && digest != "" {
-			reference = fmt.Sprintf("%s@%s", tag, digest)
-		}
+	return r.Registry.Push(ctx, resource, meta.GetVersion(), meta.GetName(), mediaType, reader)
+}
 
-		return &v1alpha1.SourceReference{
-			Registry:   ref.Host,
-			Repository: strings.TrimLeft(ref.Repository, "/"),
-			Reference:  reference,
-			Tag:        tag,
-			Digest:     digest,
-		}, nil
-	case *helm.AccessSpec:
-		return &v1alpha1.SourceReference{
-			Registry:   access.HelmRepository,
-			Repository: access.GetChartName(),
-			Reference:  access.GetVersion(),
-		}, nil
-	case *github.AccessSpec:
-		gitHubURL, err := giturls.Parse(access.RepoURL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse GitHub URL: %w", err)
-		}
+// blobUnchanged returns true if the resource was already reconciled with the same version and digest of the blob.
+func blobUnchanged(resource *v1alpha1.Resource, resourceAccess ocmctx.ResourceAccess) bool {
+	meta := resourceAccess.Meta()
+	current := resource.Status.Resource
 
-		// In the current OCM spec, the commit is mandatory, while the reference is optional. The ocm v1 lib ignores any
-		// reference. This is why, we set the coomit as reference.
-		// (See spec https://github.com/open-component-model/ocm-spec/blob/7bfbc171e814e73d6e95cfa07cc85813f89a1d44/doc/04-extensions/02-access-types/github.md)
-		return &v1alpha1.SourceReference{
-			Registry:   fmt.Sprintf("%s://%s", gitHubURL.Scheme, gitHubURL.Host),
-			Repository: gitHubURL.Path,
-			Reference:  access.Commit,
-		}, nil
-	case *git.AccessSpec:
-		gitURL, err := giturls.Parse(access.Repository)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse Git URL: %w", err)
+	return current != nil && meta.Digest != nil &&
+		current.Version == meta.GetVersion() && current.Digest == meta.Digest.String()
+}
+
+// verifiedBlob is the blob of a resource that is downloaded to a temporary file when it is opened the first time. The
+// digest of the downloaded blob is verified, unless the verification is skipped. The artifacts are created from the
+// downloaded blob, as fetching the blob again from its source could return another content than was verified.
+type verifiedBlob struct {
+	access     ocmctx.ResourceAccess
+	cv         ocmctx.ComponentVersionAccess
+	skipVerify bool
+
+	file      string
+	mediaType string
+}
+
+// Open returns a reader of the verified blob and the media type of the blob.
+func (b *verifiedBlob) Open() (io.ReadCloser, string, error) {
+	if b.file == "" {
+		if err := b.fetch(); err != nil {
+			return nil, "", err
 		}
+	}
 
-		reference, err := parseReference(access.Commit, access.Ref)
+	file, err := os.Open(b.file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open blob: %w", err)
+	}
+
+	return file, b.mediaType, nil
+}
+
+// Remove deletes the downloaded blob.
+func (b *verifiedBlob) Remove() {
+	if b.file != "" {
+		_ = os.Remove(b.file)
+	}
+}
+
+// fetch downloads the blob to a temporary file and verifies its digest.
+func (b *verifiedBlob) fetch() (err error) {
+	method, err := b.access.AccessMethod()
+	if err != nil {
+		return fmt.Errorf("failed to get access method: %w", err)
+	}
+	defer method.Close()
+
+	reader, err := method.Reader()
+	if err != nil {
+		return fmt.Errorf("failed to get blob reader: %w", err)
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp("", "resource-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary blob file: %w", err)
+	}
+	defer func() {
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse reference: %w", err)
+			_ = os.Remove(tmp.Name())
 		}
+	}()
 
-		return &v1alpha1.SourceReference{
-			Registry:   fmt.Sprintf("%s://%s", gitURL.Scheme, gitURL.Host),
-			Repository: gitURL.Path,
-			Reference:  reference,
-		}, nil
-	default:
-		logger.Info("skip setting reference for resource as no source reference is available for this access type", "access type", access)
+	if _, err = io.Copy(tmp, reader); err != nil {
+		_ = tmp.Close()
 
-		return nil, nil
+		return fmt.Errorf("failed to download blob: %w", err)
 	}
-}
 
-func parseReference(commit, ref string) (string, error) {
-	var reference string
-	switch {
-	case commit != "":
-		reference = commit
-	case ref != "":
-		reference = ref
-	default:
-		return "", fmt.Errorf("no commit or reference specified")
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob file: %w", err)
 	}
 
-	return reference, nil
+	if !b.skipVerify {
+		downloaded := blobaccess.ForFile(method.MimeType(), tmp.Name())
+		defer downloaded.Close()
+
+		if err = ocm.VerifyResourceBlob(b.access, b.cv, downloaded); err != nil {
+			return fmt.Errorf("failed to verify downloaded blob: %w", err)
+		}
+	}
+
+	b.file = tmp.Name()
+	b.mediaType = method.MimeType()
+
+	return nil
 }
 
 // setResourceStatus updates the resource status with the all required information.
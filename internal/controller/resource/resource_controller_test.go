@@ -4,7 +4,9 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/mandelsoft/vfs/pkg/osfs"
 	"github.com/mandelsoft/vfs/pkg/projectionfs"
@@ -23,6 +25,7 @@ import (
 	"ocm.software/ocm/api/ocm/extensions/repositories/ctf"
 	"ocm.software/ocm/api/utils/accessio"
 	"ocm.software/ocm/api/utils/mime"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	corev1 "k8s.io/api/core/v1"
@@ -30,6 +33,8 @@ import (
 	environment "ocm.software/ocm/api/helper/env"
 
 	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/artifact"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
 	"github.com/open-component-model/ocm-k8s-toolkit/internal/test"
 )
@@ -197,6 +202,7 @@ var _ = Describe("Resource Controller", func() {
 					Registry:   "oci://ghcr.io/stefanprodan/charts",
 					Repository: "podinfo",
 					Reference:  "6.7.1",
+					Tag:        "6.7.1",
 				},
 			),
 			Entry("GitHub access", func() string {
@@ -670,5 +676,138 @@ var _ = Describe("Resource Controller", func() {
 			By("deleting the resource")
 			test.DeleteObject(ctx, k8sClient, resourceObj)
 		})
+
+		It("stores the blob of the resource as artifact and deletes stale artifacts", func(ctx SpecContext) {
+			By("creating a CTF")
+			ctfName := "artifact"
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, "1.0.0", artifacttypes.PLAIN_TEXT, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, []byte("Hello World!"))
+						})
+					})
+				})
+			})
+
+			ctfPath := filepath.Join(tempDir, ctfName)
+			spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, ctfPath)
+			Expect(err).NotTo(HaveOccurred())
+			specData, err := spec.MarshalJSON()
+			Expect(err).NotTo(HaveOccurred())
+
+			By("mocking a component")
+			componentObj = test.MockComponent(
+				ctx,
+				componentObjName,
+				namespace.GetName(),
+				&test.MockComponentOptions{
+					Client:   k8sClient,
+					Recorder: recorder,
+					Info: v1alpha1.ComponentInfo{
+						Component:      componentName,
+						Version:        componentVersion,
+						RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+					},
+					Repository: repositoryName,
+				},
+			)
+
+			By("creating a resource")
+			resourceObj := &v1alpha1.Resource{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace.GetName(),
+				},
+				Spec: v1alpha1.ResourceSpec{
+					ComponentRef: corev1.LocalObjectReference{
+						Name: componentObj.GetName(),
+					},
+					Resource: v1alpha1.ResourceID{
+						ByReference: v1alpha1.ResourceReference{
+							Resource: ocmmetav1.NewIdentity(resourceName),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resourceObj)).To(Succeed())
+
+			// The reconciler of the suite does not store artifacts, so the resource is reconciled with a reconciler
+			// that does.
+			storage, err := artifact.NewStorage(GinkgoT().TempDir(), "artifacts.example.com")
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := &Reconciler{
+				BaseReconciler: &ocm.BaseReconciler{
+					Client:        k8sClient,
+					Scheme:        testEnv.Scheme,
+					EventRecorder: recorder,
+				},
+				Storage: storage,
+			}
+			artifactFile := func(stored *v1alpha1.Artifact) string {
+				return filepath.Join(storage.BasePath, "resource", namespace.GetName(), resourceName,
+					strings.TrimPrefix(stored.Digest, "sha256:")+".tar.gz")
+			}
+			reconcileArtifact := func(revision string) *v1alpha1.Artifact {
+				GinkgoHelper()
+
+				stored := &v1alpha1.Resource{}
+				Eventually(func(g Gomega, ctx context.Context) {
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(resourceObj)})
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(resourceObj), stored)).To(Succeed())
+					g.Expect(stored.Status.Artifact).NotTo(BeNil())
+					g.Expect(stored.Status.Artifact.Revision).To(Equal(revision))
+				}).WithContext(ctx).Should(Succeed())
+
+				return stored.Status.Artifact
+			}
+
+			By("checking that the artifact has been stored")
+			stored := reconcileArtifact("1.0.0")
+			Expect(stored.URL).To(Equal(fmt.Sprintf("http://artifacts.example.com/resource/%s/%s/%s.tar.gz",
+				namespace.GetName(), resourceName, strings.TrimPrefix(stored.Digest, "sha256:"))))
+			info, err := os.Stat(artifactFile(stored))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*stored.Size).To(Equal(info.Size()))
+
+			By("checking that the unchanged blob is not stored again")
+			Expect(reconcileArtifact("1.0.0")).To(Equal(stored))
+			unchanged, err := os.Stat(artifactFile(stored))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unchanged.ModTime()).To(Equal(info.ModTime()))
+
+			By("updating the component version with a new resource")
+			componentVersionUpdated := "v1.0.1"
+			env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+				env.Component(componentName, func() {
+					env.Version(componentVersion, func() {
+						env.Resource(resourceName, "1.0.0", artifacttypes.PLAIN_TEXT, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, []byte("Hello World!"))
+						})
+					})
+				})
+				env.Component(componentName, func() {
+					env.Version(componentVersionUpdated, func() {
+						env.Resource(resourceName, "1.0.1", artifacttypes.PLAIN_TEXT, ocmmetav1.LocalRelation, func() {
+							env.BlobData(mime.MIME_TEXT, []byte("Hello Universe!"))
+						})
+					})
+				})
+			})
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(componentObj), componentObj)).To(Succeed())
+			componentObj.Status.Component.Version = componentVersionUpdated
+			Expect(k8sClient.Status().Update(ctx, componentObj)).To(Succeed())
+
+			By("checking that the new artifact has been stored and the stale artifact has been deleted")
+			updated := reconcileArtifact("1.0.1")
+			Expect(updated.Digest).NotTo(Equal(stored.Digest))
+			Expect(artifactFile(updated)).To(BeARegularFile())
+			Expect(artifactFile(stored)).NotTo(BeAnExistingFile())
+
+			By("deleting the resource")
+			test.DeleteObject(ctx, k8sClient, resourceObj)
+		})
 	})
 })
@@ -0,0 +1,334 @@
+package resourceset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fluxcd/pkg/runtime/patch"
+	"github.com/mandelsoft/goutils/sliceutils"
+	"k8s.io/apimachinery/pkg/types"
+	"ocm.software/ocm/api/datacontext"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmctx "ocm.software/ocm/api/ocm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/status"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/util"
+)
+
+const (
+	// componentIndex is the index of the name of the component referenced by a resource set.
+	componentIndex = "spec.componentRef.name"
+	// maxNameLength is the maximum length of the name of a Resource created by a resource set.
+	maxNameLength = 253
+)
+
+// invalidNameChars matches the characters that are not allowed in the name of a Resource.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+type Reconciler struct {
+	*ocm.BaseReconciler
+}
+
+var _ ocm.Reconciler = (*Reconciler)(nil)
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	// Build index for resource sets that reference a component to make sure that we get notified when a component
+	// changes.
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &v1alpha1.ResourceSet{}, componentIndex, func(obj client.Object) []string {
+		set, ok := obj.(*v1alpha1.ResourceSet)
+		if !ok {
+			return nil
+		}
+
+		return []string{set.Spec.ComponentRef.Name}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ResourceSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Recreate Resources that were deleted or changed by someone else.
+		Owns(&v1alpha1.Resource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			// Watch for changes to components that are referenced by a resource set.
+			&v1alpha1.Component{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				component, ok := obj.(*v1alpha1.Component)
+				if !ok {
+					return []reconcile.Request{}
+				}
+
+				list := &v1alpha1.ResourceSetList{}
+				if err := r.List(ctx, list, client.InNamespace(component.GetNamespace()),
+					client.MatchingFields{componentIndex: component.GetName()}); err != nil {
+					return []reconcile.Request{}
+				}
+
+				requests := make([]reconcile.Request, 0, len(list.Items))
+				for _, set := range list.Items {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{
+							Namespace: set.GetNamespace(),
+							Name:      set.GetName(),
+						},
+					})
+				}
+
+				return requests
+			})).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=delivery.ocm.software,resources=resourcesets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=delivery.ocm.software,resources=resourcesets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=delivery.ocm.software,resources=resourcesets/finalizers,verbs=update
+
+//nolint:funlen // we do not want to cut the function at arbitrary points
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+	logger.Info("starting reconciliation")
+
+	set := &v1alpha1.ResourceSet{}
+	if err := r.Get(ctx, req.NamespacedName, set); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	patchHelper := patch.NewSerialPatcher(set, r.Client)
+	defer func(ctx context.Context) {
+		err = status.UpdateStatus(ctx, patchHelper, set, r.EventRecorder, set.GetRequeueAfter(), err)
+	}(ctx)
+
+	// The created Resources are owned by the resource set and garbage collected with it.
+	if set.Spec.Suspend || !set.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	component, err := util.GetReadyObject[v1alpha1.Component, *v1alpha1.Component](ctx, r.Client, client.ObjectKey{
+		Namespace: set.GetNamespace(),
+		Name:      set.Spec.ComponentRef.Name,
+	})
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.ResourceIsNotAvailable, err.Error())
+
+		if errors.Is(err, util.NotReadyError{}) || errors.Is(err, util.DeletionError{}) {
+			logger.Info("component is not available", "error", err)
+
+			// return no requeue as we watch the object for changes anyway
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("failed to get ready component: %w", err)
+	}
+
+	logger.Info("reconciling resource set")
+	octx := ocmctx.New(datacontext.MODE_EXTENDED)
+	defer func() {
+		err = octx.Finalize()
+	}()
+
+	session := ocmctx.NewSession(datacontext.NewSession())
+	// automatically close the session when the ocm context is closed in the above defer
+	octx.Finalizer().Close(session)
+
+	configs, err := ocm.GetEffectiveConfig(ctx, r.GetClient(), set)
+	if err != nil {
+		status.MarkNotReady(r.GetEventRecorder(), set, v1alpha1.ConfigureContextFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get effective config: %w", err)
+	}
+
+	verifications, err := ocm.GetVerifications(ctx, r.GetClient(), component)
+	if err != nil {
+		status.MarkNotReady(r.GetEventRecorder(), set, v1alpha1.ConfigureContextFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get verifications: %w", err)
+	}
+
+	if err := ocm.ConfigureContext(ctx, octx, r.GetClient(), configs, verifications); err != nil {
+		status.MarkNotReady(r.GetEventRecorder(), set, v1alpha1.ConfigureContextFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to configure context: %w", err)
+	}
+
+	spec, err := octx.RepositorySpecForConfig(component.Status.Component.RepositorySpec.Raw, nil)
+	if err != nil {
+		status.MarkNotReady(r.GetEventRecorder(), set, v1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to get repository spec: %w", err)
+	}
+
+	repo, err := session.LookupRepository(octx, spec)
+	if err != nil {
+		status.MarkNotReady(r.GetEventRecorder(), set, v1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to lookup repository: %w", err)
+	}
+
+	cv, err := session.LookupComponentVersion(repo, component.Status.Component.Component, component.Status.Component.Version)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to lookup component version: %w", err)
+	}
+
+	cds, err := ocm.VerifyComponentVersionAndListDescriptors(ctx, octx, cv, sliceutils.Transform(component.Spec.Verify, func(verify v1alpha1.Verification) string {
+		return verify.Signature
+	}))
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.GetComponentVersionFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to list verified descriptors: %w", err)
+	}
+
+	// The resources of referenced component versions are only selected if the resource set is recursive
+	if !set.Spec.Recursive {
+		cds = nil
+	}
+
+	selected, err := ocm.SelectResources(cv.GetDescriptor(), cds, set.Spec.Selector)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.GetOCMResourceFailedReason, err.Error())
+
+		return ctrl.Result{}, fmt.Errorf("failed to select resources: %w", err)
+	}
+
+	entries, err := r.createOrUpdateResources(ctx, set, selected)
+	if err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.CreateOrUpdateFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteStaleResources(ctx, set, entries); err != nil {
+		status.MarkNotReady(r.EventRecorder, set, v1alpha1.DeletionFailedReason, err.Error())
+
+		return ctrl.Result{}, err
+	}
+
+	set.Status.Component = &v1alpha1.ComponentInfo{
+		RepositorySpec: component.Status.Component.RepositorySpec,
+		Component:      component.Status.Component.Component,
+		Version:        component.Status.Component.Version,
+	}
+	set.Status.Resources = entries
+	set.Status.EffectiveOCMConfig = configs
+
+	status.MarkReady(r.EventRecorder, set, "Applied %d resources of component version %s:%s",
+		len(entries), component.Status.Component.Component, component.Status.Component.Version)
+
+	return ctrl.Result{RequeueAfter: set.GetRequeueAfter()}, nil
+}
+
+// createOrUpdateResources creates or updates a Resource for each selected ocm resource and returns the entries of the
+// Resources.
+func (r *Reconciler) createOrUpdateResources(
+	ctx context.Context,
+	set *v1alpha1.ResourceSet,
+	selected []ocm.SelectedResource,
+) ([]v1alpha1.ResourceSetEntry, error) {
+	entries := make([]v1alpha1.ResourceSetEntry, 0, len(selected))
+	for _, s := range selected {
+		reference := v1alpha1.ResourceReference{
+			Resource:      s.Reference.Resource,
+			ReferencePath: s.Reference.ReferencePath,
+		}
+
+		name, err := resourceName(set, s)
+		if err != nil {
+			return nil, err
+		}
+
+		resource := &v1alpha1.Resource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: set.GetNamespace(),
+			},
+		}
+
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, resource, func() error {
+			resource.Spec.ComponentRef = set.Spec.ComponentRef
+			resource.Spec.Resource = v1alpha1.ResourceID{ByReference: *reference.DeepCopy()}
+			resource.Spec.OCMConfig = set.Spec.OCMConfig
+			resource.Spec.SkipVerify = set.Spec.SkipVerify
+			resource.Spec.Interval = set.Spec.Interval
+
+			return controllerutil.SetControllerReference(set, resource, r.Scheme)
+		}); err != nil {
+			return nil, fmt.Errorf("failed to create or update resource %s: %w", name, err)
+		}
+
+		entries = append(entries, v1alpha1.ResourceSetEntry{Name: name, ResourceReference: reference})
+	}
+
+	return entries, nil
+}
+
+// deleteStaleResources deletes the Resources controlled by the resource set that are not part of the entries, as their
+// ocm resource is no longer selected.
+func (r *Reconciler) deleteStaleResources(
+	ctx context.Context,
+	set *v1alpha1.ResourceSet,
+	entries []v1alpha1.ResourceSetEntry,
+) error {
+	list := &v1alpha1.ResourceList{}
+	if err := r.List(ctx, list, client.InNamespace(set.GetNamespace())); err != nil {
+		return fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	desired := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		desired[entry.Name] = struct{}{}
+	}
+
+	var errs []error
+	for i := range list.Items {
+		resource := &list.Items[i]
+		if _, ok := desired[resource.GetName()]; ok || !metav1.IsControlledBy(resource, set) {
+			continue
+		}
+
+		if err := r.Delete(ctx, resource); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, fmt.Errorf("failed to delete resource %s: %w", resource.GetName(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resourceName returns the name of the Resource created for the selected ocm resource. It consists of the name of the
+// resource set, the name of the ocm resource and a hash of its reference, as the names of ocm resources are only
+// unique together with their extra identity and component version.
+func resourceName(set *v1alpha1.ResourceSet, selected ocm.SelectedResource) (string, error) {
+	data, err := json.Marshal(selected.Reference)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal resource reference: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])[:8]
+
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(selected.Resource.Name), "-"), "-.")
+	prefix := set.GetName() + "-" + name
+	if len(prefix) > maxNameLength-len(hash)-1 {
+		prefix = strings.TrimRight(prefix[:maxNameLength-len(hash)-1], "-.")
+	}
+
+	return prefix + "-" + hash, nil
+}
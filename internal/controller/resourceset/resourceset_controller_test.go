@@ -0,0 +1,199 @@
+package resourceset
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/mandelsoft/vfs/pkg/osfs"
+	"github.com/mandelsoft/vfs/pkg/projectionfs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	. "ocm.software/ocm/api/helper/builder"
+	"ocm.software/ocm/api/ocm/compdesc"
+	ocmmetav1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
+	"ocm.software/ocm/api/ocm/extensions/artifacttypes"
+	"ocm.software/ocm/api/ocm/extensions/repositories/ctf"
+	"ocm.software/ocm/api/utils/accessio"
+	"ocm.software/ocm/api/utils/mime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	environment "ocm.software/ocm/api/helper/env"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
+	"github.com/open-component-model/ocm-k8s-toolkit/internal/test"
+)
+
+var _ = Describe("ResourceSet Controller", func() {
+	var (
+		env       *Builder
+		tempDir   string
+		namespace *corev1.Namespace
+	)
+
+	const (
+		componentName    = "ocm.software/test-component"
+		componentVersion = "v1.0.0"
+		repositoryName   = "ocm.software/test-repository"
+	)
+
+	BeforeEach(func(ctx SpecContext) {
+		tempDir = GinkgoT().TempDir()
+		fs, err := projectionfs.New(osfs.OsFs, tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		env = NewBuilder(environment.FileSystem(fs))
+
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: test.SanitizeNameForK8s(ctx.SpecReport().LeafNodeText),
+			},
+		}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	})
+
+	AfterEach(func(ctx SpecContext) {
+		Expect(env.Cleanup()).To(Succeed())
+
+		// envtest runs no garbage collector that deletes the Resources owned by a deleted resource set
+		Expect(k8sClient.DeleteAllOf(ctx, &v1alpha1.Resource{}, client.InNamespace(namespace.GetName()))).To(Succeed())
+	})
+
+	It("creates a Resource per selected resource and deletes it when it is no longer selected", func(ctx SpecContext) {
+		By("creating a CTF")
+		ctfName := "resourceSet"
+		env.OCMCommonTransport(ctfName, accessio.FormatDirectory, func() {
+			env.Component(componentName, func() {
+				env.Version(componentVersion, func() {
+					env.Resource("first", "1.0.0", artifacttypes.PLAIN_TEXT, ocmmetav1.LocalRelation, func() {
+						env.BlobData(mime.MIME_TEXT, []byte("first"))
+					})
+					env.Resource("second", "1.0.0", artifacttypes.PLAIN_TEXT, ocmmetav1.LocalRelation, func() {
+						env.BlobData(mime.MIME_TEXT, []byte("second"))
+					})
+					env.Resource("other", "1.0.0", artifacttypes.BLOB, ocmmetav1.LocalRelation, func() {
+						env.BlobData(mime.MIME_OCTET, []byte("other"))
+					})
+				})
+			})
+		})
+
+		spec, err := ctf.NewRepositorySpec(ctf.ACC_READONLY, filepath.Join(tempDir, ctfName))
+		Expect(err).NotTo(HaveOccurred())
+		specData, err := spec.MarshalJSON()
+		Expect(err).NotTo(HaveOccurred())
+
+		By("mocking a component")
+		componentObj := test.MockComponent(
+			ctx,
+			"component",
+			namespace.GetName(),
+			&test.MockComponentOptions{
+				Client:   k8sClient,
+				Recorder: recorder,
+				Info: v1alpha1.ComponentInfo{
+					Component:      componentName,
+					Version:        componentVersion,
+					RepositorySpec: &apiextensionsv1.JSON{Raw: specData},
+				},
+				Repository: repositoryName,
+			},
+		)
+		DeferCleanup(func(ctx SpecContext) {
+			test.DeleteObject(ctx, k8sClient, componentObj)
+		})
+
+		By("creating a resource set")
+		set := &v1alpha1.ResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "set",
+				Namespace: namespace.GetName(),
+			},
+			Spec: v1alpha1.ResourceSetSpec{
+				ComponentRef: corev1.LocalObjectReference{Name: componentObj.GetName()},
+				Selector:     v1alpha1.ResourceSelector{Type: artifacttypes.PLAIN_TEXT},
+				Interval:     metav1.Duration{Duration: 10 * time.Minute},
+			},
+		}
+		Expect(k8sClient.Create(ctx, set)).To(Succeed())
+
+		test.WaitForReadyObject(ctx, k8sClient, set, map[string]any{
+			"Status.Resources": HaveLen(2),
+		})
+		Expect(ownedResources(ctx, set)).To(ConsistOf(
+			HaveField("Spec.Resource.ByReference.Resource", ocmmetav1.NewIdentity("first")),
+			HaveField("Spec.Resource.ByReference.Resource", ocmmetav1.NewIdentity("second")),
+		))
+
+		By("narrowing the selector")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(set), set)).To(Succeed())
+		set.Spec.Selector.Name = "first"
+		Expect(k8sClient.Update(ctx, set)).To(Succeed())
+
+		Eventually(func(ctx context.Context) ([]v1alpha1.Resource, error) {
+			return ownedResources(ctx, set), nil
+		}, "15s").WithContext(ctx).Should(ConsistOf(
+			HaveField("Spec.Resource.ByReference.Resource", ocmmetav1.NewIdentity("first")),
+		))
+
+		test.DeleteObject(ctx, k8sClient, set)
+	})
+})
+
+// ownedResources returns the Resources controlled by the resource set.
+func ownedResources(ctx context.Context, set *v1alpha1.ResourceSet) []v1alpha1.Resource {
+	GinkgoHelper()
+
+	list := &v1alpha1.ResourceList{}
+	Expect(k8sClient.List(ctx, list, client.InNamespace(set.GetNamespace()))).To(Succeed())
+
+	var owned []v1alpha1.Resource
+	for _, resource := range list.Items {
+		if metav1.IsControlledBy(&resource, set) {
+			owned = append(owned, resource)
+		}
+	}
+
+	return owned
+}
+
+// selectedResource returns a selected resource with the name and identity.
+func selectedResource(name string, identity ocmmetav1.Identity) ocm.SelectedResource {
+	resource := &compdesc.Resource{}
+	resource.Name = name
+
+	return ocm.SelectedResource{
+		Reference: ocmmetav1.ResourceReference{Resource: identity},
+		Resource:  resource,
+	}
+}
+
+var _ = Describe("resourceName", func() {
+	set := &v1alpha1.ResourceSet{ObjectMeta: metav1.ObjectMeta{Name: "set"}}
+
+	It("derives a valid name from the name and reference of the resource", func() {
+		selected := selectedResource("My_Chart", ocmmetav1.Identity{"name": "My_Chart"})
+		name, err := resourceName(set, selected)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(MatchRegexp(`^set-my-chart-[0-9a-f]{8}$`))
+	})
+
+	It("distinguishes resources with the same name", func() {
+		amd, err := resourceName(set, selectedResource("image", ocmmetav1.Identity{"name": "image", "architecture": "amd64"}))
+		Expect(err).NotTo(HaveOccurred())
+		arm, err := resourceName(set, selectedResource("image", ocmmetav1.Identity{"name": "image", "architecture": "arm64"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(amd).NotTo(Equal(arm))
+	})
+
+	It("truncates long names", func() {
+		long := fmt.Sprintf("%0300d", 0)
+		name, err := resourceName(set, selectedResource(long, ocmmetav1.Identity{"name": long}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(name)).To(BeNumerically("<=", maxNameLength))
+	})
+})
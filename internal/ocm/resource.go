@@ -4,16 +4,183 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"ocm.software/ocm/api/ocm/compdesc"
 	"ocm.software/ocm/api/ocm/selectors"
+	"ocm.software/ocm/api/ocm/selectors/accessors"
+	"ocm.software/ocm/api/ocm/selectors/labelsel"
 	"ocm.software/ocm/api/ocm/tools/signing"
+	"ocm.software/ocm/api/utils/blobaccess"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	ocmctx "ocm.software/ocm/api/ocm"
 	v1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
 )
 
+// ResourceReferenceForID returns the reference of the resource identified by the resource id in the component
+// descriptor. A selector is resolved to the identity of the single resource of the component descriptor that matches
+// it.
+func ResourceReferenceForID(cd *compdesc.ComponentDescriptor, id v1alpha1.ResourceID) (v1.ResourceReference, error) {
+	switch {
+	case id.BySelector != nil:
+		resource, err := SelectResource(cd, *id.BySelector)
+		if err != nil {
+			return v1.ResourceReference{}, err
+		}
+
+		return v1.ResourceReference{Resource: resource.GetIdentity(cd.GetResources())}, nil
+	case len(id.ByReference.Resource) > 0:
+		return v1.ResourceReference{
+			Resource:      id.ByReference.Resource,
+			ReferencePath: id.ByReference.ReferencePath,
+		}, nil
+	default:
+		return v1.ResourceReference{}, errors.New("resource must be identified by reference or by selector")
+	}
+}
+
+// SelectedResource is a resource selected from a component version or from one of its referenced component versions.
+type SelectedResource struct {
+	// Reference is the reference of the resource relative to the component version it was selected from.
+	Reference v1.ResourceReference
+	// Resource is the selected resource.
+	Resource *compdesc.Resource
+}
+
+// SelectResource returns the single resource of the component descriptor that matches all criteria of the selector. It
+// fails if no or several resources match.
+func SelectResource(cd *compdesc.ComponentDescriptor, selector v1alpha1.ResourceSelector) (*compdesc.Resource, error) {
+	selected, err := SelectResources(cd, nil, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(selected) {
+	case 0:
+		return nil, fmt.Errorf("no resource of component version %s:%s matches the selector",
+			cd.GetName(), cd.GetVersion())
+	case 1:
+		return selected[0].Resource, nil
+	default:
+		identities := make([]string, 0, len(selected))
+		for _, s := range selected {
+			identities = append(identities, s.Reference.Resource.String())
+		}
+
+		return nil, fmt.Errorf("%d resources of component version %s:%s match the selector, it must select a single "+
+			"resource: %s", len(selected), cd.GetName(), cd.GetVersion(), strings.Join(identities, ", "))
+	}
+}
+
+// SelectResources returns all resources of the component descriptor that match all criteria of the selector. If the
+// descriptors of the referenced component versions are passed, the resources of the referenced component versions are
+// selected as well, with the reference path to their component version.
+func SelectResources(
+	cd *compdesc.ComponentDescriptor,
+	cds *Descriptors,
+	selector v1alpha1.ResourceSelector,
+) ([]SelectedResource, error) {
+	sel, err := resourceSelectors(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolver compdesc.ComponentVersionResolver
+	if cds != nil {
+		resolver = compdesc.NewComponentVersionSet(cds.List...)
+	}
+
+	var (
+		selected []SelectedResource
+		walk     func(cd *compdesc.ComponentDescriptor, path []v1.Identity, visited []string) error
+	)
+	walk = func(cd *compdesc.ComponentDescriptor, path []v1.Identity, visited []string) error {
+		key := cd.GetName() + ":" + cd.GetVersion()
+		if slices.Contains(visited, key) {
+			return fmt.Errorf("component version %s references itself", key)
+		}
+		visited = append(visited, key)
+
+		resources, err := cd.SelectResources(sel...)
+		if err != nil {
+			return fmt.Errorf("failed to select resources of component version %s: %w", key, err)
+		}
+
+		for i := range resources {
+			resource := &resources[i]
+			selected = append(selected, SelectedResource{
+				Reference: v1.ResourceReference{
+					Resource:      resource.GetIdentity(cd.GetResources()),
+					ReferencePath: slices.Clone(path),
+				},
+				Resource: resource,
+			})
+		}
+
+		if resolver == nil {
+			return nil
+		}
+
+		for i := range cd.References {
+			ref := &cd.References[i]
+			refCD, err := resolver.LookupComponentVersion(ref.ComponentName, ref.Version)
+			if err != nil {
+				return fmt.Errorf("failed to get descriptor of component version %s:%s referenced by %s: %w",
+					ref.ComponentName, ref.Version, key, err)
+			}
+
+			if err := walk(refCD, append(slices.Clone(path), ref.GetIdentity(cd.References)), visited); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(cd, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return selected, nil
+}
+
+// resourceSelectors returns the OCM resource selectors for the criteria of the selector. OCM only selects names
+// literally, so the name pattern is matched by a selector function.
+func resourceSelectors(selector v1alpha1.ResourceSelector) ([]selectors.ResourceSelector, error) {
+	var sel []selectors.ResourceSelector
+	if selector.Name != "" {
+		name, err := regexp.Compile("^(?:" + selector.Name + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid name pattern of resource selector: %w", err)
+		}
+
+		sel = append(sel, selectors.ResourceSelectorFunc(
+			func(_ accessors.ElementListAccessor, resource accessors.ResourceAccessor) bool {
+				return name.MatchString(resource.GetMeta().GetName())
+			},
+		))
+	}
+
+	if selector.Type != "" {
+		sel = append(sel, selectors.ArtifactType(selector.Type))
+	}
+
+	if len(selector.ExtraIdentity) > 0 {
+		sel = append(sel, selectors.PartialIdentity(selector.ExtraIdentity))
+	}
+
+	for key, value := range selector.Labels {
+		sel = append(sel, selectors.Label(labelsel.Name(key), labelsel.Value(value)))
+	}
+
+	return sel, nil
+}
+
 func GetResourceAccessForComponentVersion(
 	ctx context.Context,
 	cv ocmctx.ComponentVersionAccess,
@@ -62,12 +229,29 @@ func verifyResource(access ocmctx.ResourceAccess, cv ocmctx.ComponentVersionAcce
 		return fmt.Errorf("failed to create access method: %w", err)
 	}
 
+	return verifyResourceBlob(access, cv, cd, accessMethod.AsBlobAccess())
+}
+
+// VerifyResourceBlob verifies the digest of the blob, e.g. a downloaded copy of the blob of the resource, with the
+// digest of the resource from the component version access and its component descriptor.
+func VerifyResourceBlob(access ocmctx.ResourceAccess, cv ocmctx.ComponentVersionAccess, blob blobaccess.BlobAccess) error {
+	return verifyResourceBlob(access, cv, cv.GetDescriptor(), blob)
+}
+
+// verifyResourceBlob verifies the digest of the blob with the digest from the component version access and component
+// descriptor.
+func verifyResourceBlob(
+	access ocmctx.ResourceAccess,
+	cv ocmctx.ComponentVersionAccess,
+	cd *compdesc.ComponentDescriptor,
+	blob blobaccess.BlobAccess,
+) error {
 	// Add the component descriptor to the local verified store, so its digest will be compared with the digest from the
 	// component version access
 	store := signing.NewLocalVerifiedStore()
 	store.Add(cd)
 
-	ok, err := signing.VerifyResourceDigestByResourceAccess(cv, access, accessMethod.AsBlobAccess(), store)
+	ok, err := signing.VerifyResourceDigestByResourceAccess(cv, access, blob, store)
 	if !ok {
 		if err != nil {
 			return fmt.Errorf("verification failed: %w", err)
@@ -0,0 +1,102 @@
+package ocm_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"ocm.software/ocm/api/ocm/compdesc"
+
+	v1 "ocm.software/ocm/api/ocm/compdesc/meta/v1"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+	. "github.com/open-component-model/ocm-k8s-toolkit/internal/ocm"
+)
+
+var _ = Describe("resource selection", func() {
+	resource := func(name, typ string, extraIdentity v1.Identity, labels map[string]string) compdesc.Resource {
+		res := compdesc.Resource{}
+		res.Name = name
+		res.Version = Version1
+		res.Type = typ
+		res.ExtraIdentity = extraIdentity
+		for key, value := range labels {
+			raw, err := json.Marshal(value)
+			Expect(err).NotTo(HaveOccurred())
+			res.Labels = append(res.Labels, v1.Label{Name: key, Value: raw})
+		}
+
+		return res
+	}
+
+	cd := &compdesc.ComponentDescriptor{}
+	cd.Name = TestComponent
+	cd.Version = Version1
+	cd.Resources = compdesc.Resources{
+		resource("frontend-chart", "helmChart", nil, map[string]string{"role": "frontend"}),
+		resource("backend-chart", "helmChart", nil, map[string]string{"role": "backend"}),
+		resource("frontend-image", "ociImage", v1.Identity{"architecture": "amd64"}, map[string]string{"role": "frontend"}),
+		resource("frontend-image-arm", "ociImage", v1.Identity{"architecture": "arm64"}, map[string]string{"role": "frontend"}),
+	}
+
+	It("selects the single resource of a type with a label", func() {
+		res, err := SelectResource(cd, v1alpha1.ResourceSelector{
+			Type:   "helmChart",
+			Labels: map[string]string{"role": "frontend"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Name).To(Equal("frontend-chart"))
+	})
+
+	It("selects by extra identity", func() {
+		res, err := SelectResource(cd, v1alpha1.ResourceSelector{
+			ExtraIdentity: map[string]string{"architecture": "arm64"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Name).To(Equal("frontend-image-arm"))
+	})
+
+	It("matches the whole name against the pattern", func() {
+		res, err := SelectResource(cd, v1alpha1.ResourceSelector{Name: "backend-.*"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.Name).To(Equal("backend-chart"))
+
+		_, err = SelectResource(cd, v1alpha1.ResourceSelector{Name: "chart"})
+		Expect(err).To(MatchError(ContainSubstring("no resource")))
+	})
+
+	It("fails if no resource matches", func() {
+		_, err := SelectResource(cd, v1alpha1.ResourceSelector{Type: "blob"})
+		Expect(err).To(MatchError(ContainSubstring("no resource of component version")))
+	})
+
+	It("fails if several resources match", func() {
+		_, err := SelectResource(cd, v1alpha1.ResourceSelector{Type: "ociImage"})
+		Expect(err).To(MatchError(ContainSubstring("2 resources of component version")))
+	})
+
+	It("rejects an invalid name pattern", func() {
+		_, err := SelectResource(cd, v1alpha1.ResourceSelector{Name: "("})
+		Expect(err).To(MatchError(ContainSubstring("invalid name pattern")))
+	})
+
+	It("selects all resources that match", func() {
+		selected, err := SelectResources(cd, nil, v1alpha1.ResourceSelector{Type: "ociImage"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selected).To(HaveLen(2))
+		for _, s := range selected {
+			Expect(s.Resource.Type).To(Equal("ociImage"))
+			Expect(s.Reference.ReferencePath).To(BeEmpty())
+		}
+	})
+
+	It("resolves a selector to the identity of the selected resource", func() {
+		ref, err := ResourceReferenceForID(cd, v1alpha1.ResourceID{
+			BySelector: &v1alpha1.ResourceSelector{Type: "helmChart", Labels: map[string]string{"role": "backend"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref.Resource).To(HaveKeyWithValue("name", "backend-chart"))
+		Expect(ref.ReferencePath).To(BeEmpty())
+	})
+})
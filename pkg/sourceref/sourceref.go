@@ -0,0 +1,344 @@
+// Package sourceref determines the source references of OCM resources from their access specifications. The source
+// reference tells consumers where to fetch a resource from without the controller downloading it. Converters for
+// access types that are not supported out of the box can be registered with Register.
+package sourceref
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"ocm.software/ocm/api/oci"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/git"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/github"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/helm"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/localblob"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/maven"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/npm"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/ociartifact"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/ociblob"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/s3"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/wget"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	giturls "github.com/chainguard-dev/git-urls"
+	ocmctx "ocm.software/ocm/api/ocm"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// Converter determines the source reference of a resource from its access specification. It returns nil if the access
+// specification does not point to a source a consumer can fetch the resource from.
+type Converter[T any] func(
+	ctx context.Context,
+	access T,
+	cv ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error)
+
+type converter func(ctx context.Context, accSpec any, cv ocmctx.ComponentVersionAccess) (*v1alpha1.SourceReference, error)
+
+var (
+	convertersLock sync.RWMutex
+	converters     = map[reflect.Type]converter{}
+)
+
+// Register registers the converter for the access specifications of type T, which must be the (pointer) type the
+// access specifications are decoded into, e.g. *ociartifact.AccessSpec. It allows to support access types that are
+// not supported out of the box, e.g. by registering converters in the main package of a custom build of the
+// controller. A registered converter replaces the converter of the same type.
+func Register[T any](conv Converter[T]) {
+	var access T
+	typ := reflect.TypeOf(access)
+	if typ == nil {
+		panic("source reference converters must be registered for a concrete access specification type")
+	}
+
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+
+	converters[typ] = func(
+		ctx context.Context,
+		accSpec any,
+		cv ocmctx.ComponentVersionAccess,
+	) (*v1alpha1.SourceReference, error) {
+		return conv(ctx, accSpec.(T), cv) //nolint:forcetypeassert // converters are looked up by the type
+	}
+}
+
+func init() {
+	Register(ociArtifactSourceRef)
+	Register(ociBlobSourceRef)
+	Register(localBlobSourceRef)
+	Register(helmSourceRef)
+	Register(gitHubSourceRef)
+	Register(gitSourceRef)
+	Register(s3SourceRef)
+	Register(npmSourceRef)
+	Register(mavenSourceRef)
+	Register(wgetSourceRef)
+}
+
+// ForAccessSpec determines the source reference for a given access specification with the converter registered for
+// its type. It extracts relevant information such as registry, repository, and reference details. It returns nil if
+// no converter is registered for the type.
+func ForAccessSpec(ctx context.Context, accSpec any, cv ocmctx.ComponentVersionAccess) (*v1alpha1.SourceReference, error) {
+	convertersLock.RLock()
+	conv, ok := converters[reflect.TypeOf(accSpec)]
+	convertersLock.RUnlock()
+
+	if !ok {
+		log.FromContext(ctx).Info("skip setting reference for resource as no source reference is available for this access type", "access type", accSpec)
+
+		return nil, nil
+	}
+
+	return conv(ctx, accSpec, cv)
+}
+
+func ociArtifactSourceRef(
+	_ context.Context,
+	access *ociartifact.AccessSpec,
+	cv ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	ociURLDigest, err := access.GetOCIReference(cv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OCI reference: %w", err)
+	}
+
+	// TODO: Replace with another reference parser that is not ocm v1 lib (@frewilhelm)
+	//   Why is it needed in the first place?
+	//   Because if a reference consists of a tag and a digest, we need to store both of them.
+	//   Additionally, consuming resources, as a HelmRelease or OCIRepository, might need the tag, the digest, or
+	//   both of them. Thus, we have to offer some flexibility here.
+	//   ocm v2 lib offers a LooseReference that is able to parse a reference with a tag and a digest. However, the
+	//   functionality is placed in an internal package and not available for us (yet).
+	ref, err := oci.ParseRef(ociURLDigest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OCI reference: %w", err)
+	}
+
+	var tag string
+	if ref.Tag != nil {
+		tag = *ref.Tag
+	}
+
+	var digest string
+	if ref.Digest != nil {
+		digest = ref.Digest.String()
+	}
+
+	var reference string
+	if tag != "" && digest != "" {
+		reference = fmt.Sprintf("%s@%s", tag, digest)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   ref.Host,
+		Repository: strings.TrimLeft(ref.Repository, "/"),
+		Reference:  reference,
+		Tag:        tag,
+		Digest:     digest,
+	}, nil
+}
+
+// ociBlobSourceRef references the blob by the registry and repository it is stored in and its digest.
+func ociBlobSourceRef(
+	_ context.Context,
+	access *ociblob.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	ref, err := oci.ParseRef(access.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OCI reference: %w", err)
+	}
+
+	digest := access.Digest.String()
+
+	return &v1alpha1.SourceReference{
+		Registry:   ref.Host,
+		Repository: strings.TrimLeft(ref.Repository, "/"),
+		Reference:  digest,
+		Digest:     digest,
+	}, nil
+}
+
+// localBlobSourceRef references a local blob by its global access. Local blobs of component versions stored in an OCI
+// registry are layers of the component version artifact and are globally accessible as OCI blobs. Other local blobs,
+// e.g. of a common transport archive, cannot be referenced.
+func localBlobSourceRef(
+	ctx context.Context,
+	access *localblob.AccessSpec,
+	cv ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	global := access.GlobalAccessSpec(cv.GetContext())
+	if global == nil {
+		log.FromContext(ctx).Info("skip setting reference for resource as the local blob has no global access",
+			"local reference", access.LocalReference)
+
+		return nil, nil
+	}
+
+	return ForAccessSpec(ctx, global, cv)
+}
+
+// helmSourceRef references the chart by its repository, name and version. Charts of OCI based helm repositories are
+// additionally referenced by tag and digest, as their version can contain both.
+func helmSourceRef(
+	_ context.Context,
+	access *helm.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	ref := &v1alpha1.SourceReference{
+		Registry:   access.HelmRepository,
+		Repository: access.GetChartName(),
+		Reference:  access.GetVersion(),
+	}
+
+	if strings.HasPrefix(access.HelmRepository, "oci://") {
+		ref.Tag, ref.Digest, _ = strings.Cut(access.GetVersion(), "@")
+	}
+
+	return ref, nil
+}
+
+func gitHubSourceRef(
+	_ context.Context,
+	access *github.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	gitHubURL, err := giturls.Parse(access.RepoURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub URL: %w", err)
+	}
+
+	// In the current OCM spec, the commit is mandatory, while the reference is optional. The ocm v1 lib ignores any
+	// reference. This is why, we set the coomit as reference.
+	// (See spec https://github.com/open-component-model/ocm-spec/blob/7bfbc171e814e73d6e95cfa07cc85813f89a1d44/doc/04-extensions/02-access-types/github.md)
+	return &v1alpha1.SourceReference{
+		Registry:   fmt.Sprintf("%s://%s", gitHubURL.Scheme, gitHubURL.Host),
+		Repository: gitHubURL.Path,
+		Reference:  access.Commit,
+	}, nil
+}
+
+func gitSourceRef(
+	_ context.Context,
+	access *git.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	gitURL, err := giturls.Parse(access.Repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Git URL: %w", err)
+	}
+
+	reference, err := parseReference(access.Commit, access.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reference: %w", err)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   fmt.Sprintf("%s://%s", gitURL.Scheme, gitURL.Host),
+		Repository: gitURL.Path,
+		Reference:  reference,
+	}, nil
+}
+
+// s3SourceRef references the object by the endpoint of its region, its bucket and key and its (optional) version. The
+// endpoint is the global endpoint if the region is not set.
+func s3SourceRef(
+	_ context.Context,
+	access *s3.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	endpoint := "https://s3.amazonaws.com"
+	if access.Region != "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", access.Region)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   endpoint,
+		Repository: path.Join(access.Bucket, access.Key),
+		Reference:  access.Version,
+	}, nil
+}
+
+// npmSourceRef references the package by its registry, name and version.
+func npmSourceRef(
+	_ context.Context,
+	access *npm.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	return &v1alpha1.SourceReference{
+		Registry:   access.Registry,
+		Repository: access.Package,
+		Reference:  access.Version,
+		Tag:        access.Version,
+	}, nil
+}
+
+// mavenSourceRef references the artifact by its repository, its path in the repository and its version. If the
+// classifier or the extension is set, only a single file of the artifact is accessed, which is then referenced by its
+// path in the repository.
+func mavenSourceRef(
+	_ context.Context,
+	access *maven.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	if access.Coordinates == nil {
+		return nil, fmt.Errorf("maven access of repository %s has no coordinates", access.RepoUrl)
+	}
+
+	repository := path.Join(strings.ReplaceAll(access.GroupId, ".", "/"), access.ArtifactId)
+	if access.Classifier != nil || access.Extension != nil {
+		file := access.ArtifactId + "-" + access.Version
+		if access.Classifier != nil && *access.Classifier != "" {
+			file += "-" + *access.Classifier
+		}
+		if access.Extension != nil && *access.Extension != "" {
+			file += "." + *access.Extension
+		}
+		repository = path.Join(repository, access.Version, file)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   access.RepoUrl,
+		Repository: repository,
+		Reference:  access.Version,
+	}, nil
+}
+
+// wgetSourceRef references the file by the host and the path and query of its URL, as the query can be required to
+// download the file, e.g. for pre-signed URLs.
+func wgetSourceRef(
+	_ context.Context,
+	access *wget.AccessSpec,
+	_ ocmctx.ComponentVersionAccess,
+) (*v1alpha1.SourceReference, error) {
+	u, err := url.Parse(access.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	return &v1alpha1.SourceReference{
+		Registry:   fmt.Sprintf("%s://%s", u.Scheme, u.Host),
+		Repository: u.RequestURI(),
+	}, nil
+}
+
+func parseReference(commit, ref string) (string, error) {
+	var reference string
+	switch {
+	case commit != "":
+		reference = commit
+	case ref != "":
+		reference = ref
+	default:
+		return "", fmt.Errorf("no commit or reference specified")
+	}
+
+	return reference, nil
+}
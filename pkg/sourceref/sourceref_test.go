@@ -0,0 +1,157 @@
+package sourceref
+
+import (
+	"context"
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/helm"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/localblob"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/maven"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/npm"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/ociblob"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/s3"
+	"ocm.software/ocm/api/ocm/extensions/accessmethods/wget"
+
+	ocmctx "ocm.software/ocm/api/ocm"
+
+	"github.com/open-component-model/ocm-k8s-toolkit/api/v1alpha1"
+)
+
+// inHouseAccessSpec is an access specification of an access type that is not supported out of the box.
+type inHouseAccessSpec struct {
+	Location string
+}
+
+// componentVersion is a component version access that only provides the OCM context, which is all the converters
+// need.
+type componentVersion struct {
+	ocmctx.ComponentVersionAccess
+}
+
+func (componentVersion) GetContext() ocmctx.Context {
+	return ocmctx.DefaultContext()
+}
+
+var _ = Describe("ForAccessSpec", func() {
+	DescribeTable("determines the source reference of an access specification",
+		func(accSpec any, expected *v1alpha1.SourceReference) {
+			ref, err := ForAccessSpec(context.Background(), accSpec, componentVersion{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ref).To(Equal(expected))
+		},
+		Entry("OCI blob", &ociblob.AccessSpec{
+			Reference: "ghcr.io/acme/component-descriptors/acme.org/app",
+			Digest:    "sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+		}, &v1alpha1.SourceReference{
+			Registry:   "ghcr.io",
+			Repository: "acme/component-descriptors/acme.org/app",
+			Reference:  "sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+			Digest:     "sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+		}),
+		Entry("local blob with a global OCI blob access", localblob.New(
+			"sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c", "", "application/x-tar",
+			ociblob.New(
+				"ghcr.io/acme/component-descriptors/acme.org/app",
+				"sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+				"application/x-tar",
+				1024,
+			),
+		), &v1alpha1.SourceReference{
+			Registry:   "ghcr.io",
+			Repository: "acme/component-descriptors/acme.org/app",
+			Reference:  "sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+			Digest:     "sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c",
+		}),
+		Entry("local blob without global access", localblob.New(
+			"sha256:8c6c2b2a2d5f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c8f3e1b5c8a6f1d0c", "", "application/x-tar", nil,
+		), nil),
+		Entry("Helm chart of an OCI based repository", &helm.AccessSpec{
+			HelmRepository: "oci://ghcr.io/stefanprodan/charts",
+			HelmChart:      "podinfo:6.7.1",
+		}, &v1alpha1.SourceReference{
+			Registry:   "oci://ghcr.io/stefanprodan/charts",
+			Repository: "podinfo",
+			Reference:  "6.7.1",
+			Tag:        "6.7.1",
+		}),
+		Entry("S3 object", &s3.AccessSpec{
+			Region:  "eu-central-1",
+			Bucket:  "artifacts",
+			Key:     "app/app.tgz",
+			Version: "v1",
+		}, &v1alpha1.SourceReference{
+			Registry:   "https://s3.eu-central-1.amazonaws.com",
+			Repository: "artifacts/app/app.tgz",
+			Reference:  "v1",
+		}),
+		Entry("S3 object without region", &s3.AccessSpec{
+			Bucket: "artifacts",
+			Key:    "app/app.tgz",
+		}, &v1alpha1.SourceReference{
+			Registry:   "https://s3.amazonaws.com",
+			Repository: "artifacts/app/app.tgz",
+		}),
+		Entry("npm package", &npm.AccessSpec{
+			Registry: "https://registry.npmjs.org",
+			Package:  "yargs",
+			Version:  "17.7.1",
+		}, &v1alpha1.SourceReference{
+			Registry:   "https://registry.npmjs.org",
+			Repository: "yargs",
+			Reference:  "17.7.1",
+			Tag:        "17.7.1",
+		}),
+		Entry("Maven artifact", maven.New("https://repo1.maven.org/maven2", "org.acme.app", "app-server", "1.2.0"),
+			&v1alpha1.SourceReference{
+				Registry:   "https://repo1.maven.org/maven2",
+				Repository: "org/acme/app/app-server",
+				Reference:  "1.2.0",
+			}),
+		Entry("file of a Maven artifact", maven.New("https://repo1.maven.org/maven2", "org.acme.app", "app-server", "1.2.0",
+			maven.WithClassifier("linux-amd64"), maven.WithExtension("tar.gz")),
+			&v1alpha1.SourceReference{
+				Registry:   "https://repo1.maven.org/maven2",
+				Repository: "org/acme/app/app-server/1.2.0/app-server-1.2.0-linux-amd64.tar.gz",
+				Reference:  "1.2.0",
+			}),
+		Entry("wget URL", &wget.AccessSpec{
+			URL: "https://example.com/downloads/app.tgz",
+		}, &v1alpha1.SourceReference{
+			Registry:   "https://example.com",
+			Repository: "/downloads/app.tgz",
+		}),
+		Entry("wget URL with query", &wget.AccessSpec{
+			URL: "https://example.com/downloads/app.tgz?X-Amz-Expires=3600&X-Amz-Signature=abc",
+		}, &v1alpha1.SourceReference{
+			Registry:   "https://example.com",
+			Repository: "/downloads/app.tgz?X-Amz-Expires=3600&X-Amz-Signature=abc",
+		}),
+		Entry("unsupported access type", &inHouseAccessSpec{Location: "somewhere"}, nil),
+	)
+
+	It("fails for a Maven access without coordinates", func() {
+		_, err := ForAccessSpec(context.Background(), &maven.AccessSpec{RepoUrl: "https://repo1.maven.org/maven2"}, nil)
+		Expect(err).To(MatchError(ContainSubstring("has no coordinates")))
+	})
+
+	It("uses converters registered for other access types", func() {
+		Register(func(
+			_ context.Context,
+			access *inHouseAccessSpec,
+			_ ocmctx.ComponentVersionAccess,
+		) (*v1alpha1.SourceReference, error) {
+			return &v1alpha1.SourceReference{Registry: "in-house", Repository: access.Location}, nil
+		})
+		DeferCleanup(func() {
+			convertersLock.Lock()
+			defer convertersLock.Unlock()
+			delete(converters, reflect.TypeOf(&inHouseAccessSpec{}))
+		})
+
+		ref, err := ForAccessSpec(context.Background(), &inHouseAccessSpec{Location: "storage/app"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ref).To(Equal(&v1alpha1.SourceReference{Registry: "in-house", Repository: "storage/app"}))
+	})
+})
@@ -0,0 +1,13 @@
+package sourceref
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSourceRef(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "source reference test")
+}